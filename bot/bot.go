@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/bwmarrin/discordgo"
 
@@ -13,21 +14,51 @@ import (
 	"github.com/hakasec/japanbot-go/bot/database"
 	"github.com/hakasec/japanbot-go/bot/database/models"
 	"github.com/hakasec/japanbot-go/bot/database/set"
+	"github.com/hakasec/japanbot-go/bot/datafile"
 	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/pitch"
+	"github.com/hakasec/japanbot-go/bot/tatoeba"
 )
 
 // JapanBot is a Discord bot with Japanese parsing abilities
 type JapanBot struct {
-	dictionary    *dictionary.Dictionary
+	kanjidic      *dictionary.Kanjidic
+	radicalFiles  *dictionary.Radicals
+	accents       *pitch.Accents
 	configuration *config.BotConfiguration
 	session       *discordgo.Session
 	db            *database.DBConnection
 	handlers      HandlerMap
 
-	channels *set.DBSet
-	cards    *set.DBSet
+	channels    *set.DBSet
+	cards       *set.DBSet
+	preferences *set.DBSet
 
 	analyseRequests map[string][]string
+	// references holds the cross-references last shown to each user
+	references map[string][]dictionary.Reference
+	// requestsLock guards analyseRequests and references, which are
+	// shared by handlers running at the same time
+	requestsLock sync.RWMutex
+
+	// examplesCorpus is loaded in the background, use exampleCorpus
+	examplesCorpus *tatoeba.Corpus
+	examplesLock   sync.RWMutex
+
+	// dicts is swapped when the dictionary is reloaded, use dictionaries,
+	// dictionary or sources
+	dicts     *dictionaries
+	dictsLock sync.RWMutex
+	// reloading is set while the dictionary is being reloaded
+	reloading int32
+}
+
+// dictionaries are the dictionaries loaded from the JMdict file and the
+// configured sources, which are reloaded together. dictionary is held in
+// memory or read from the database, depending on dictionary_store
+type dictionaries struct {
+	dictionary dictionary.Words
+	sources    *dictionary.Sources
 }
 
 // Start starts the JapanBot instance
@@ -92,21 +123,57 @@ func (b *JapanBot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCre
 
 // New creates a new instance of JapanBot using a given config
 func New(config *config.BotConfiguration) (*JapanBot, error) {
-	r, err := os.Open(config.JMdictFile)
+	files, err := findDictionaryFiles(config)
 	if err != nil {
 		return nil, err
 	}
 
-	d, err := dictionary.Load(r)
+	db, err := database.OpenFromConfig(&config.DBConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	db, err := database.OpenFromConfig(&config.DBConfig)
+	dicts, err := loadDictionaries(files, db)
 	if err != nil {
 		return nil, err
 	}
 
+	var k *dictionary.Kanjidic
+	if files.KanjidicFile != "" {
+		r, err := datafile.Open(files.KanjidicFile)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		k, err = dictionary.LoadKanjidic(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", files.KanjidicFile, err.Error())
+		}
+	}
+
+	var radicals *dictionary.Radicals
+	if config.KradFile != "" && config.RadkFile != "" {
+		radicals, err = loadRadicals(config.KradFile, config.RadkFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var accents *pitch.Accents
+	if config.AccentsFile != "" {
+		r, err := os.Open(config.AccentsFile)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		accents, err = pitch.Load(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	channelSet := set.New("channels", reflect.TypeOf(models.Channel{}), db)
 	err = channelSet.CreateTable()
 	if err != nil {
@@ -117,17 +184,31 @@ func New(config *config.BotConfiguration) (*JapanBot, error) {
 	if err != nil {
 		return nil, err
 	}
+	preferenceSet := set.New("preferences", reflect.TypeOf(models.Preference{}), db)
+	err = preferenceSet.CreateTable()
+	if err != nil {
+		return nil, err
+	}
 
 	b := &JapanBot{
-		dictionary:    d,
+		kanjidic:      k,
+		radicalFiles:  radicals,
+		accents:       accents,
 		db:            db,
 		configuration: config,
 
-		channels: channelSet,
-		cards:    cardSet,
+		channels:    channelSet,
+		cards:       cardSet,
+		preferences: preferenceSet,
 
 		analyseRequests: make(map[string][]string),
+		references:      make(map[string][]dictionary.Reference),
+
+		dicts: dicts,
 	}
 	b.handlers = b.createHandlerMap()
+	if config.TatoebaPairsFile != "" && config.TatoebaIndicesFile != "" {
+		go b.loadExamples()
+	}
 	return b, nil
 }
@@ -0,0 +1,131 @@
+// Package cache stores data built from large files as gob, so that it
+// can be loaded quickly until the files it was built from change.
+// Each cache starts with a header of its version and the size and
+// checksum of each file it was built from
+package cache
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// magic identifies a cache file
+const magic = "japanbot-cache"
+
+// ErrStale is returned when a cache is missing or out of date
+var ErrStale = errors.New("cache is stale")
+
+// Stamp identifies the contents of a source file
+type Stamp struct {
+	Path     string
+	Size     int64
+	Checksum uint32
+}
+
+// header starts every cache file
+type header struct {
+	Magic   string
+	Version int
+	Sources []Stamp
+}
+
+// Path returns the path of the cache for a file, which is kept next to it
+func Path(file string) string {
+	return file + ".cache"
+}
+
+// Stamps stamps each source file with its size and CRC-32 checksum,
+// which is much quicker than parsing even a large file
+func Stamps(sources ...string) ([]Stamp, error) {
+	stamps := make([]Stamp, len(sources))
+	for i, source := range sources {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		hash := crc32.NewIEEE()
+		size, err := io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		stamps[i] = Stamp{Path: source, Size: size, Checksum: hash.Sum32()}
+	}
+	return stamps, nil
+}
+
+// Read decodes the cache at path into v. It returns ErrStale if the cache
+// doesn't exist, has a different version or any source has changed
+func Read(path string, version int, v interface{}, sources ...string) error {
+	stamps, err := Stamps(sources...)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return ErrStale
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := gob.NewDecoder(f)
+	var h header
+	if err := dec.Decode(&h); err != nil || h.Magic != magic {
+		return ErrStale
+	}
+	if h.Version != version || !sameStamps(h.Sources, stamps) {
+		return ErrStale
+	}
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return nil
+}
+
+// Write encodes v to the cache at path. The cache is written to a
+// temporary file first so a partial cache is never read
+func Write(path string, version int, v interface{}, sources ...string) error {
+	stamps, err := Stamps(sources...)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	enc := gob.NewEncoder(f)
+	err = enc.Encode(header{Magic: magic, Version: version, Sources: stamps})
+	if err == nil {
+		err = enc.Encode(v)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func sameStamps(a, b []Stamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
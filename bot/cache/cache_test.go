@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testData struct {
+	Words map[string][]int
+}
+
+func TestReadWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "words.txt")
+	if err := ioutil.WriteFile(source, []byte("日本\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := Path(source)
+
+	var data testData
+	if err := Read(path, 1, &data, source); err != ErrStale {
+		t.Errorf("expected a missing cache to be stale, got %v", err)
+	}
+
+	written := testData{Words: map[string][]int{"日本": {1, 2}}}
+	if err := Write(path, 1, written, source); err != nil {
+		t.Fatal(err)
+	}
+	if err := Read(path, 1, &data, source); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Words["日本"]) != 2 {
+		t.Errorf("expected the written data, got %v", data)
+	}
+
+	if err := Read(path, 2, &data, source); err != ErrStale {
+		t.Errorf("expected a different version to be stale, got %v", err)
+	}
+	if err := ioutil.WriteFile(source, []byte("日本語\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Read(path, 1, &data, source); err != ErrStale {
+		t.Errorf("expected a changed source to be stale, got %v", err)
+	}
+}
@@ -9,10 +9,58 @@ import (
 type BotConfiguration struct {
 	JMdictFile string `json:"jmdict_file"`
 	APIToken   string `json:"api_token"`
+	// DictionaryStore is memory to keep JMdict in memory, which is the
+	// default, or sqlite to import it into the database and only keep
+	// what's needed to split phrases into words in memory
+	DictionaryStore string `json:"dictionary_store"`
+	// JMnedictFile is the JMnedict file of proper names used by analyse and
+	// jpn!name, which are left out if this is blank
+	JMnedictFile string `json:"jmnedict_file"`
+	// KanjidicFile is the KANJIDIC2 file used by jpn!kanji, which is
+	// disabled if this is blank
+	KanjidicFile string `json:"kanjidic_file"`
+	// KradFile and RadkFile are the UTF-8 KRADFILE and RADKFILE used by
+	// jpn!radicals and jpn!bycomponent, which are disabled if either is blank
+	KradFile string `json:"kradfile"`
+	RadkFile string `json:"radkfile"`
+	// KanjiVGDir is the directory of KanjiVG SVG files used by jpn!strokes,
+	// which is disabled if this is blank
+	KanjiVGDir string `json:"kanjivg_dir"`
+
+	// AccentsFile is a tab separated pitch accent file, such as the Kanjium
+	// accents.txt, and accents aren't shown if this is blank
+	AccentsFile string `json:"accents_file"`
+	// TatoebaPairsFile is a tab separated file of Japanese and English
+	// Tatoeba sentence pairs, and TatoebaIndicesFile is the Jpn_Indices file
+	// of the words they use. Example sentences are disabled if either is blank
+	TatoebaPairsFile   string `json:"tatoeba_pairs_file"`
+	TatoebaIndicesFile string `json:"tatoeba_indices_file"`
+	// OwnerIDs are the Discord user IDs allowed to use jpn!reload,
+	// which is disabled if this is empty
+	OwnerIDs []string `json:"owner_ids"`
+	// AnnouncementsChannel is the ID of a channel the words added, removed
+	// and changed are posted to when the dictionary is reloaded, which
+	// isn't done if this is blank
+	AnnouncementsChannel string `json:"announcements_channel"`
+	// Sources lists dictionaries to look words up in as well as JMdict
+	Sources []SourceConfiguration `json:"sources"`
 
 	DBConfig DBConfiguration `json:"db_config"`
 }
 
+// SourceConfiguration configures an additional dictionary source
+type SourceConfiguration struct {
+	// Type is jmdict for a file in the JMdict format, jmnedict, glossary
+	// for a tab separated custom glossary or yomichan for a Yomichan zip
+	Type string `json:"type"`
+	// Name is shown next to the source's words, which for Yomichan
+	// dictionaries defaults to their title
+	Name string `json:"name"`
+	File string `json:"file"`
+	// Fallback sources are only used for words earlier sources don't have
+	Fallback bool `json:"fallback"`
+}
+
 // DBConfiguration configures the database layer
 type DBConfiguration struct {
 	DriverName string `json:"driver_name"`
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/helpers"
+	"github.com/hakasec/japanbot-go/bot/inflection"
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+func (b *JapanBot) conjugate(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a word!")
+		return
+	}
+
+	word := kana.FromInput(strings.Join(args[1:], ""))
+	d := b.dictionary()
+	entries := d.Lookup(word)
+	if len(entries) == 0 {
+		// allow conjugated words to be entered too
+		if deinflections := d.Deinflect(kana.Normalize(word)); len(deinflections) > 0 {
+			word = deinflections[0].Key
+			entries = d.Lookup(word)
+		}
+	}
+
+	var (
+		conjugated []string
+		tables     []string
+	)
+	for _, entry := range entries {
+		// conjugate the word as entered, or the headword if it was normalised
+		form := word
+		if !d.Contains(word) {
+			form = dictionary.Headword(entry)
+		}
+
+		for _, sense := range entry.Senses {
+			for _, pos := range sense.POS {
+				code := dictionary.TagCode(pos)
+				if helpers.StringSliceContains(conjugated, form+code) {
+					continue
+				}
+				conjugated = append(conjugated, form+code)
+
+				forms, err := inflection.Conjugate(form, code)
+				if err != nil {
+					continue
+				}
+				tables = append(tables, buildConjugationTable(form, code, forms))
+			}
+		}
+	}
+
+	if len(tables) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "I don't know how to conjugate that word!")
+		return
+	}
+	for _, table := range tables {
+		s.ChannelMessageSend(m.ChannelID, table)
+	}
+}
+
+func buildConjugationTable(word, posCode string, forms []inflection.Form) string {
+	width := 0
+	for _, form := range forms {
+		if len(form.Name) > width {
+			width = len(form.Name)
+		}
+	}
+
+	var message strings.Builder
+	message.WriteString(
+		fmt.Sprintf("```\n%s (%s)\n\n", word, dictionary.TagDescription(posCode)),
+	)
+	for _, form := range forms {
+		padding := strings.Repeat(" ", width-len(form.Name))
+		if form.Polite != "" {
+			message.WriteString(
+				fmt.Sprintf("%s:%s %s / %s\n", form.Name, padding, form.Plain, form.Polite),
+			)
+		} else {
+			message.WriteString(
+				fmt.Sprintf("%s:%s %s\n", form.Name, padding, form.Plain),
+			)
+		}
+	}
+	message.WriteString("```")
+	return message.String()
+}
@@ -18,3 +18,12 @@ type Card struct {
 	Phrase    string    `model:"phrase"`
 	Timestamp time.Time `model:"timestamp"`
 }
+
+// Preference is a db model for the display settings of a user or channel,
+// where the settings of a user override those of the channel
+type Preference struct {
+	UID           int    `model:"uid,primarykey,auto"`
+	OwnerID       string `model:"owner_id,unique"`
+	RomajiStyle   string `model:"romaji_style"`
+	DoubledVowels int    `model:"doubled_vowels,0"`
+}
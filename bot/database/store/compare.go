@@ -0,0 +1,97 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+// digestRow is a row of jmdict_entries without the entry itself
+type digestRow struct {
+	id     int64
+	seq    string
+	digest int64
+}
+
+// digests reads the ID and digest of every entry, in the order they were imported
+func (s *Store) digests() ([]digestRow, error) {
+	var result []digestRow
+	err := s.eachRow("SELECT id, seq, digest FROM jmdict_entries ORDER BY id", func(rows *sql.Rows) error {
+		var row digestRow
+		if err := rows.Scan(&row.id, &row.seq, &row.digest); err != nil {
+			return err
+		}
+		result = append(result, row)
+		return nil
+	})
+	return result, err
+}
+
+// Compare compares the entries of a store with those of the store before
+// it was reloaded, which reads the other set of tables. Entries are
+// matched by their IDs as dictionary.Compare matches them, and only the
+// entries which were added, removed or have a different digest are loaded
+func (s *Store) Compare(before *Store) (*dictionary.Diff, error) {
+	oldRows, err := before.digests()
+	if err != nil {
+		return nil, err
+	}
+	newRows, err := s.digests()
+	if err != nil {
+		return nil, err
+	}
+
+	// the first entry with each ID is compared, as in dictionary.Compare
+	old := make(map[string]digestRow, len(oldRows))
+	for _, row := range oldRows {
+		if _, ok := old[row.seq]; !ok {
+			old[row.seq] = row
+		}
+	}
+	var (
+		added, modified, removed = []int64{}, []int64{}, []int64{}
+		modifiedOld              = []int64{}
+		seqs                     = make(map[string]bool, len(newRows))
+	)
+	for _, row := range newRows {
+		seqs[row.seq] = true
+		if o, ok := old[row.seq]; !ok {
+			added = append(added, row.id)
+		} else if o.digest != row.digest {
+			modified = append(modified, row.id)
+			modifiedOld = append(modifiedOld, o.id)
+		}
+	}
+	for _, row := range oldRows {
+		if !seqs[row.seq] {
+			removed = append(removed, row.id)
+		}
+	}
+
+	diff := &dictionary.Diff{}
+	if diff.Added, err = s.loadEntries(added, false); err != nil {
+		return nil, err
+	}
+	if diff.Removed, err = before.loadEntries(removed, false); err != nil {
+		return nil, err
+	}
+	newEntries, err := s.loadEntrySet(modified, false)
+	if err != nil {
+		return nil, err
+	}
+	oldEntries, err := before.loadEntrySet(modifiedOld, false)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range modified {
+		entry, oldEntry := newEntries.byID[id], oldEntries.byID[modifiedOld[i]]
+		if entry == nil || oldEntry == nil {
+			continue
+		}
+		// a different digest doesn't always mean Compare would see a change
+		if m, ok := dictionary.CompareEntries(oldEntry, entry); ok {
+			diff.Modified = append(diff.Modified, m)
+		}
+	}
+	return diff, nil
+}
@@ -0,0 +1,227 @@
+package store
+
+import (
+	"database/sql"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+// entrySet collects entries as their rows are read
+type entrySet struct {
+	entries []*jmdict.Entry
+	byID    map[int64]*jmdict.Entry
+}
+
+// loadEntries reads entries by their IDs, or every entry if ids is nil,
+// in the order they were imported. Outlines only have their kanji,
+// readings and the parts of speech of their senses
+func (s *Store) loadEntries(ids []int64, outline bool) ([]*jmdict.Entry, error) {
+	set, err := s.loadEntrySet(ids, outline)
+	if err != nil {
+		return nil, err
+	}
+	return set.entries, nil
+}
+
+// loadEntrySet is loadEntries, keeping the entries by their IDs
+func (s *Store) loadEntrySet(ids []int64, outline bool) (*entrySet, error) {
+	set := &entrySet{byID: make(map[int64]*jmdict.Entry)}
+	if ids != nil && len(ids) == 0 {
+		return set, nil
+	}
+	where := func(column string) string {
+		if ids == nil {
+			return ""
+		}
+		return " WHERE " + column + " IN (" + idList(ids) + ")"
+	}
+
+	err := s.eachRow("SELECT id, seq FROM jmdict_entries"+where("id")+" ORDER BY id", func(rows *sql.Rows) error {
+		var (
+			id    int64
+			entry = &jmdict.Entry{}
+		)
+		if err := rows.Scan(&id, &entry.EntryID); err != nil {
+			return err
+		}
+		set.entries = append(set.entries, entry)
+		set.byID[id] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []struct {
+		query string
+		read  func(rows *sql.Rows) error
+	}{
+		{"SELECT entry, phrase FROM jmdict_kanji" + where("entry") + " ORDER BY entry, position", set.readKanji},
+		{"SELECT entry, phrase, no_kanji FROM jmdict_readings" + where("entry") + " ORDER BY entry, position", set.readReading},
+		{"SELECT entry FROM jmdict_senses" + where("entry") + " ORDER BY entry, position", set.readSense},
+		{"SELECT entry, element, position, kind, value FROM jmdict_tags" + where("entry") + " ORDER BY rowid", set.tagReader(outline)},
+	}
+	if !outline {
+		queries = append(queries, []struct {
+			query string
+			read  func(rows *sql.Rows) error
+		}{
+			{"SELECT entry, sense, language, gender, type, definition FROM jmdict_glosses" + where("entry") + " ORDER BY id", set.readGloss},
+			{"SELECT entry, sense, language, type, wasei FROM jmdict_loanwords" + where("entry") + " ORDER BY rowid", set.readLoanword},
+		}...)
+	}
+	for _, q := range queries {
+		if err := s.eachRow(q.query, q.read); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// eachRow calls f with each row of a query of the store's set of tables
+func (s *Store) eachRow(query string, f func(rows *sql.Rows) error, args ...interface{}) error {
+	rows, err := s.db.Query(s.names.Replace(query), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := f(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (set *entrySet) readKanji(rows *sql.Rows) error {
+	var (
+		id    int64
+		kanji jmdict.KanjiElement
+	)
+	if err := rows.Scan(&id, &kanji.Phrase); err != nil {
+		return err
+	}
+	if entry, ok := set.byID[id]; ok {
+		entry.KanjiElements = append(entry.KanjiElements, kanji)
+	}
+	return nil
+}
+
+func (set *entrySet) readReading(rows *sql.Rows) error {
+	var (
+		id      int64
+		reading jmdict.ReadingElement
+	)
+	if err := rows.Scan(&id, &reading.Phrase, &reading.PhraseNoKanji); err != nil {
+		return err
+	}
+	if entry, ok := set.byID[id]; ok {
+		entry.ReadingElements = append(entry.ReadingElements, reading)
+	}
+	return nil
+}
+
+func (set *entrySet) readSense(rows *sql.Rows) error {
+	var id int64
+	if err := rows.Scan(&id); err != nil {
+		return err
+	}
+	if entry, ok := set.byID[id]; ok {
+		entry.Senses = append(entry.Senses, jmdict.Sense{})
+	}
+	return nil
+}
+
+// tagReader reads tags, keeping only priorities and parts of speech for outlines
+func (set *entrySet) tagReader(outline bool) func(rows *sql.Rows) error {
+	return func(rows *sql.Rows) error {
+		var (
+			id                   int64
+			position             int
+			element, kind, value string
+		)
+		if err := rows.Scan(&id, &element, &position, &kind, &value); err != nil {
+			return err
+		}
+		entry, ok := set.byID[id]
+		if !ok || outline && kind != priorityTag && kind != posTag {
+			return nil
+		}
+
+		var tags *[]string
+		switch {
+		case element == kanjiElement && position < len(entry.KanjiElements):
+			kanji := &entry.KanjiElements[position]
+			tags = elementTags(kind, &kanji.Info, &kanji.Priorities)
+		case element == readingElement && position < len(entry.ReadingElements):
+			reading := &entry.ReadingElements[position]
+			tags = elementTags(kind, &reading.Info, &reading.Priorities)
+		case element == senseElement && position < len(entry.Senses):
+			tags = senseTags(kind, &entry.Senses[position])
+		}
+		if tags != nil {
+			*tags = append(*tags, value)
+		}
+		return nil
+	}
+}
+
+// elementTags finds the tags of a kanji or reading element of a kind
+func elementTags(kind string, info, priorities *[]string) *[]string {
+	switch kind {
+	case infoTag:
+		return info
+	case priorityTag:
+		return priorities
+	}
+	return nil
+}
+
+// senseTags finds the tags of a sense of a kind
+func senseTags(kind string, sense *jmdict.Sense) *[]string {
+	switch kind {
+	case posTag:
+		return &sense.POS
+	case fieldTag:
+		return &sense.Fields
+	case miscTag:
+		return &sense.Misc
+	case dialectTag:
+		return &sense.Dialects
+	case referenceTag:
+		return &sense.CrossReferences
+	case antonymTag:
+		return &sense.Antonyms
+	}
+	return nil
+}
+
+func (set *entrySet) readGloss(rows *sql.Rows) error {
+	var (
+		id    int64
+		sense int
+		gloss jmdict.Glossary
+	)
+	if err := rows.Scan(&id, &sense, &gloss.Language, &gloss.Gender, &gloss.Type, &gloss.Definition); err != nil {
+		return err
+	}
+	if entry, ok := set.byID[id]; ok && sense < len(entry.Senses) {
+		entry.Senses[sense].GlossaryItems = append(entry.Senses[sense].GlossaryItems, gloss)
+	}
+	return nil
+}
+
+func (set *entrySet) readLoanword(rows *sql.Rows) error {
+	var (
+		id     int64
+		sense  int
+		source jmdict.SourceLanguage
+	)
+	if err := rows.Scan(&id, &sense, &source.Language, &source.Type, &source.Wasei); err != nil {
+		return err
+	}
+	if entry, ok := set.byID[id]; ok && sense < len(entry.Senses) {
+		entry.Senses[sense].SourceLanguages = append(entry.Senses[sense].SourceLanguages, source)
+	}
+	return nil
+}
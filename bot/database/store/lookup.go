@@ -0,0 +1,247 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+const (
+	// maxGlossCandidates is the most entries scored for a gloss search,
+	// keeping those Dictionary would rank first
+	maxGlossCandidates = 500
+	// maxKeyLength is the longest key, in runes, MatchPrefixes looks for
+	maxKeyLength = 24
+)
+
+// Lookup finds the entries for a key. If there isn't an exact match the key
+// is normalised, so words can be looked up in hiragana, katakana, half width
+// katakana or romaji
+func (s *Store) Lookup(key string) []*jmdict.Entry {
+	return s.lookup(key, false)
+}
+
+// lookup is Lookup, loading only the outlines of entries if outline is set
+func (s *Store) lookup(key string, outline bool) []*jmdict.Entry {
+	if entries := s.lookupColumn("phrase", key, outline); len(entries) > 0 {
+		return entries
+	}
+	if entries := s.lookupColumn("normalised", kana.Normalize(key), outline); len(entries) > 0 {
+		return entries
+	}
+	if input := kana.FromInput(key); input != kana.FoldWidth(key) {
+		return s.lookupColumn("normalised", kana.Normalize(input), outline)
+	}
+	return nil
+}
+
+// lookupColumn finds the entries with a kanji or reading where column is value
+func (s *Store) lookupColumn(column, value string, outline bool) []*jmdict.Entry {
+	return s.lookupQuery(
+		outline,
+		fmt.Sprintf(
+			"SELECT entry FROM jmdict_kanji WHERE %[1]s = ?1 UNION SELECT entry FROM jmdict_readings WHERE %[1]s = ?1 ORDER BY entry",
+			column,
+		),
+		value,
+	)
+}
+
+// LookupID finds the entries with an ID
+func (s *Store) LookupID(id string) []*jmdict.Entry {
+	return s.lookupQuery(false, "SELECT id FROM jmdict_entries WHERE seq = ? ORDER BY id", id)
+}
+
+// lookupQuery loads the entries, or their outlines, whose IDs a query selects
+func (s *Store) lookupQuery(outline bool, query string, args ...interface{}) []*jmdict.Entry {
+	ids, err := s.queryIDs(query, args...)
+	if err == nil {
+		var entries []*jmdict.Entry
+		if entries, err = s.loadEntries(ids, outline); err == nil {
+			return entries
+		}
+	}
+	fmt.Printf("Error looking up entries: %s\n", err.Error())
+	return nil
+}
+
+func (s *Store) queryIDs(query string, args ...interface{}) ([]int64, error) {
+	ids := []int64{}
+	err := s.eachRow(query, func(rows *sql.Rows) error {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		return nil
+	}, args...)
+	return ids, err
+}
+
+// SearchGloss finds entries with an English gloss containing every word of
+// the query, ranked as Dictionary ranks them. Glosses are found with full
+// text search when it is available, and otherwise by scanning them
+func (s *Store) SearchGloss(query string, limit int) []dictionary.GlossMatch {
+	q := dictionary.ParseGlossQuery(query)
+	words := q.Words()
+	if len(words) == 0 {
+		return nil
+	}
+
+	var (
+		candidates string
+		args       []interface{}
+	)
+	if s.fts {
+		quoted := make([]string, len(words))
+		for i, word := range words {
+			quoted[i] = `"` + strings.Replace(word, `"`, `""`, -1) + `"`
+		}
+		candidates = "SELECT g.entry, g.definition FROM jmdict_glosses_fts f JOIN jmdict_glosses g ON g.id = f.rowid " +
+			"WHERE jmdict_glosses_fts MATCH ? AND g.language IN ('', 'eng')"
+		args = append(args, strings.Join(quoted, " "))
+	} else {
+		// match whole words, as they're split by dictionary.TokeniseGloss
+		conditions := make([]string, len(words))
+		for i, word := range words {
+			conditions[i] = "(' ' || lower(definition) || ' ') GLOB ?"
+			args = append(args, "*[^a-z0-9']"+word+"[^a-z0-9']*")
+		}
+		candidates = "SELECT entry, definition FROM jmdict_glosses WHERE language IN ('', 'eng') AND " +
+			strings.Join(conditions, " AND ")
+	}
+
+	// ids isn't nil, which would load every entry
+	ids := []int64{}
+	exact := make(map[int64]bool)
+	err := s.eachRow(candidates, func(rows *sql.Rows) error {
+		var (
+			id         int64
+			definition string
+		)
+		if err := rows.Scan(&id, &definition); err != nil {
+			return err
+		}
+		if _, ok := exact[id]; !ok {
+			ids = append(ids, id)
+		}
+		exact[id] = exact[id] || q.Exact(definition)
+		return nil
+	}, args...)
+	if err == nil && len(ids) > maxGlossCandidates {
+		ids, err = s.bestCandidates(ids, exact)
+	}
+	if err != nil {
+		fmt.Printf("Error searching glosses: %s\n", err.Error())
+		return nil
+	}
+	entries, err := s.loadEntries(ids, false)
+	if err != nil {
+		fmt.Printf("Error searching glosses: %s\n", err.Error())
+		return nil
+	}
+
+	var matches []dictionary.GlossMatch
+	for _, entry := range entries {
+		for i, sense := range entry.Senses {
+			for _, gloss := range sense.GlossaryItems {
+				if gloss.Language != "" && gloss.Language != "eng" {
+					continue
+				}
+				if score, ok := q.Score(entry, i, gloss.Definition); ok {
+					matches = append(matches, dictionary.GlossMatch{
+						Entry: entry,
+						Sense: i,
+						Gloss: gloss.Definition,
+						Exact: q.Exact(gloss.Definition),
+						Score: score,
+					})
+				}
+			}
+		}
+	}
+	return dictionary.BestGlossMatches(matches, limit)
+}
+
+// bestCandidates keeps the candidates of a gloss search which Dictionary
+// would rank first, those with a gloss which is the whole query and then
+// the most common
+func (s *Store) bestCandidates(ids []int64, exact map[int64]bool) ([]int64, error) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	// outlines are loaded in the order of their IDs
+	outlines, err := s.loadEntries(ids, true)
+	if err != nil {
+		return nil, err
+	} else if len(outlines) != len(ids) {
+		return nil, fmt.Errorf("found %d of %d entries", len(outlines), len(ids))
+	}
+	priority := make(map[int64]int, len(ids))
+	for i, entry := range outlines {
+		priority[ids[i]] = dictionary.Priority(entry)
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := ids[i], ids[j]
+		if exact[a] != exact[b] {
+			return exact[a]
+		}
+		return priority[a] > priority[b]
+	})
+	return ids[:maxGlossCandidates], nil
+}
+
+// MatchPrefixes finds the keys text starts with, shortest first
+func (s *Store) MatchPrefixes(text string) []string {
+	var (
+		runes  = []rune(text)
+		folded = kana.FoldRunes(runes)
+		args   []interface{}
+	)
+	if len(runes) > maxKeyLength {
+		runes, folded = runes[:maxKeyLength], folded[:maxKeyLength]
+	}
+	for i := 1; i <= len(folded); i++ {
+		args = append(args, string(folded[:i]))
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	// keys are matched on their normalised forms, as they are in Dictionary's trie
+	found := make(map[string]bool)
+	query := fmt.Sprintf(
+		"SELECT phrase, normalised FROM jmdict_kanji WHERE normalised IN (%[1]s) "+
+			"UNION SELECT phrase, normalised FROM jmdict_readings WHERE normalised IN (%[1]s)",
+		placeholders(len(args)),
+	)
+	err := s.eachRow(query, func(rows *sql.Rows) error {
+		var phrase, normalised string
+		if err := rows.Scan(&phrase, &normalised); err != nil {
+			return err
+		}
+		found[phrase] = true
+		found[normalised] = true
+		return nil
+	}, append(args, args...)...)
+	if err != nil {
+		fmt.Printf("Error matching prefixes: %s\n", err.Error())
+		return nil
+	}
+
+	// prefer the word as written, as Dictionary does
+	var keys []string
+	for i := 1; i <= len(runes); i++ {
+		if word := string(runes[:i]); found[word] {
+			keys = append(keys, word)
+		} else if word := string(folded[:i]); found[word] {
+			keys = append(keys, word)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,460 @@
+// Package store keeps a JMdict dictionary in the database, so entries are
+// read when they're looked up instead of being held in memory
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/cache"
+	"github.com/hakasec/japanbot-go/bot/database"
+	"github.com/hakasec/japanbot-go/bot/datafile"
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// schemaVersion is changed whenever the tables change, to import again
+const schemaVersion = 2
+
+// Entries are kept in one of two sets of tables. One is read while a new
+// file is imported into the other, which is then used instead
+const (
+	firstSet  = "a"
+	secondSet = "b"
+)
+
+// importBatchSize is the number of entries imported in each transaction,
+// so other writes to the database aren't held up for the whole import
+const importBatchSize = 2000
+
+// setsTable records the file imported into each set of tables,
+// and which set is in use
+const setsTable = `CREATE TABLE IF NOT EXISTS jmdict_sets (
+	name TEXT PRIMARY KEY,
+	imported TEXT NOT NULL,
+	active INTEGER NOT NULL
+)`
+
+// tables creates a set of tables, whose names are rewritten to those of
+// the set. Entries have the dictionary.Digest of their contents, to
+// compare sets without loading every entry. Tags hold the info and priority tags of kanji and readings,
+// and the tags, cross-references and antonyms of senses, in the order
+// they appear in JMdict
+var tables = []string{
+	`CREATE TABLE IF NOT EXISTS jmdict_entries (
+		id INTEGER PRIMARY KEY,
+		seq TEXT NOT NULL,
+		digest INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_entries_seq ON jmdict_entries (seq)`,
+	`CREATE TABLE IF NOT EXISTS jmdict_kanji (
+		entry INTEGER NOT NULL REFERENCES jmdict_entries (id),
+		position INTEGER NOT NULL,
+		phrase TEXT NOT NULL,
+		normalised TEXT NOT NULL,
+		PRIMARY KEY (entry, position)
+	)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_kanji_phrase ON jmdict_kanji (phrase)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_kanji_normalised ON jmdict_kanji (normalised)`,
+	`CREATE TABLE IF NOT EXISTS jmdict_readings (
+		entry INTEGER NOT NULL REFERENCES jmdict_entries (id),
+		position INTEGER NOT NULL,
+		phrase TEXT NOT NULL,
+		no_kanji TEXT NOT NULL,
+		normalised TEXT NOT NULL,
+		PRIMARY KEY (entry, position)
+	)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_readings_phrase ON jmdict_readings (phrase)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_readings_normalised ON jmdict_readings (normalised)`,
+	`CREATE TABLE IF NOT EXISTS jmdict_senses (
+		entry INTEGER NOT NULL REFERENCES jmdict_entries (id),
+		position INTEGER NOT NULL,
+		PRIMARY KEY (entry, position)
+	)`,
+	`CREATE TABLE IF NOT EXISTS jmdict_glosses (
+		id INTEGER PRIMARY KEY,
+		entry INTEGER NOT NULL REFERENCES jmdict_entries (id),
+		sense INTEGER NOT NULL,
+		language TEXT NOT NULL,
+		gender TEXT NOT NULL,
+		type TEXT NOT NULL,
+		definition TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_glosses_entry ON jmdict_glosses (entry)`,
+	`CREATE TABLE IF NOT EXISTS jmdict_loanwords (
+		entry INTEGER NOT NULL REFERENCES jmdict_entries (id),
+		sense INTEGER NOT NULL,
+		language TEXT NOT NULL,
+		type TEXT NOT NULL,
+		wasei TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_loanwords_entry ON jmdict_loanwords (entry)`,
+	`CREATE TABLE IF NOT EXISTS jmdict_tags (
+		entry INTEGER NOT NULL REFERENCES jmdict_entries (id),
+		element TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		value TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS jmdict_tags_entry ON jmdict_tags (entry)`,
+}
+
+// setTables are the tables of a set, other than the full text index,
+// in the order they can be dropped
+var setTables = []string{
+	"jmdict_tags", "jmdict_loanwords", "jmdict_glosses", "jmdict_senses",
+	"jmdict_readings", "jmdict_kanji", "jmdict_entries",
+}
+
+// ftsTable indexes glosses for full text search, which needs go-sqlite3
+// to be built with the sqlite_fts5 tag
+const ftsTable = `CREATE VIRTUAL TABLE IF NOT EXISTS jmdict_glosses_fts USING fts5(
+	definition,
+	content='jmdict_glosses',
+	content_rowid='id'
+)`
+
+// the elements tags belong to
+const (
+	kanjiElement   = "k"
+	readingElement = "r"
+	senseElement   = "s"
+)
+
+// the kinds of tag
+const (
+	infoTag      = "info"
+	priorityTag  = "priority"
+	posTag       = "pos"
+	fieldTag     = "field"
+	miscTag      = "misc"
+	dialectTag   = "dialect"
+	referenceTag = "xref"
+	antonymTag   = "antonym"
+)
+
+// Store is a JMdict dictionary kept in the database
+type Store struct {
+	db   *database.DBConnection
+	info dictionary.SourceInfo
+	// set is the set of tables the store reads, and names rewrites the
+	// tables in queries to those of the set
+	set   string
+	names *strings.Replacer
+	// fts is set if full text search is available
+	fts bool
+}
+
+// New creates a Store in a database
+func New(db *database.DBConnection) *Store {
+	s := &Store{db: db, info: dictionary.SourceInfo{Name: "JMdict"}}
+	s.useSet(firstSet)
+	return s
+}
+
+func (s *Store) useSet(set string) {
+	s.set = set
+	s.names = strings.NewReplacer("jmdict_", "jmdict_"+set+"_")
+}
+
+// otherSet returns the set of tables the store isn't reading
+func (s *Store) otherSet() string {
+	if s.set == firstSet {
+		return secondSet
+	}
+	return firstSet
+}
+
+// CreateTables creates the tables of the store if they don't exist, reading
+// the set of tables in use. Full text search is only used if SQLite
+// supports FTS5
+func (s *Store) CreateTables() error {
+	if _, err := s.db.Exec(setsTable); err != nil {
+		return err
+	}
+	var active string
+	err := s.db.QueryRow("SELECT name FROM jmdict_sets WHERE active = 1").Scan(&active)
+	if err == nil {
+		s.useSet(active)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	if err := s.createSet(); err != nil {
+		return err
+	}
+	if !s.fts {
+		fmt.Println("Full text search isn't available, build with -tags sqlite_fts5")
+	}
+	return nil
+}
+
+// createSet creates the tables of the store's set if they don't exist
+func (s *Store) createSet() error {
+	for _, table := range tables {
+		if _, err := s.db.Exec(s.names.Replace(table)); err != nil {
+			return err
+		}
+	}
+	_, err := s.db.Exec(s.names.Replace(ftsTable))
+	s.fts = err == nil
+	return nil
+}
+
+// dropSet drops the tables of the store's set
+func (s *Store) dropSet() error {
+	// the full text index can only be dropped if SQLite supports FTS5,
+	// and is left unused otherwise
+	s.db.Exec(s.names.Replace("DROP TABLE IF EXISTS jmdict_glosses_fts"))
+	for _, table := range setTables {
+		if _, err := s.db.Exec(s.names.Replace("DROP TABLE IF EXISTS " + table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import imports a JMdict file unless it is already in use, imported with
+// the same support for full text search since the index is only filled
+// when importing. The file is imported into the set of tables which isn't
+// in use, which the store reads once it's done. Other stores keep reading
+// the tables they were using. progress is called as the file is read if
+// it isn't nil
+func (s *Store) Import(file string, progress dictionary.Progress) error {
+	stamps, err := cache.Stamps(file)
+	if err != nil {
+		return err
+	}
+	imported := fmt.Sprintf("%d %d %d %t", schemaVersion, stamps[0].Size, stamps[0].Checksum, s.fts)
+	var current string
+	err = s.db.QueryRow("SELECT imported FROM jmdict_sets WHERE name = ? AND active = 1", s.set).Scan(&current)
+	if err == nil && current == imported {
+		return nil
+	} else if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	r, err := datafile.Open(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	next := New(s.db)
+	next.useSet(s.otherSet())
+	if err := next.dropSet(); err != nil {
+		return err
+	}
+	if err := next.createSet(); err != nil {
+		return err
+	}
+	if err := next.importEntries(r, r.Size(), progress); err != nil {
+		return fmt.Errorf("%s: %s", file, err.Error())
+	}
+	if err := next.activate(imported); err != nil {
+		return err
+	}
+	s.useSet(next.set)
+	s.fts = next.fts
+	return nil
+}
+
+// activate records the file imported into the store's set,
+// making it the set in use
+func (s *Store) activate(imported string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE jmdict_sets SET active = 0"); err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.Exec(
+		"INSERT OR REPLACE INTO jmdict_sets (name, imported, active) VALUES (?, ?, 1)",
+		s.set, imported,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// statements are the prepared inserts used when importing. fts is only
+// prepared if full text search is available
+type statements struct {
+	entry, kanji, reading, sense, gloss, loanword, tag, fts *sql.Stmt
+}
+
+func (s *Store) prepareStatements(tx *sql.Tx) (*statements, error) {
+	var (
+		st  statements
+		err error
+	)
+	prepare := func(stmt **sql.Stmt, query string) {
+		if err == nil {
+			*stmt, err = tx.Prepare(s.names.Replace(query))
+		}
+	}
+	prepare(&st.entry, "INSERT INTO jmdict_entries (id, seq, digest) VALUES (?, ?, ?)")
+	prepare(&st.kanji, "INSERT INTO jmdict_kanji (entry, position, phrase, normalised) VALUES (?, ?, ?, ?)")
+	prepare(&st.reading, "INSERT INTO jmdict_readings (entry, position, phrase, no_kanji, normalised) VALUES (?, ?, ?, ?, ?)")
+	prepare(&st.sense, "INSERT INTO jmdict_senses (entry, position) VALUES (?, ?)")
+	prepare(&st.gloss, "INSERT INTO jmdict_glosses (entry, sense, language, gender, type, definition) VALUES (?, ?, ?, ?, ?, ?)")
+	prepare(&st.loanword, "INSERT INTO jmdict_loanwords (entry, sense, language, type, wasei) VALUES (?, ?, ?, ?, ?)")
+	prepare(&st.tag, "INSERT INTO jmdict_tags (entry, element, position, kind, value) VALUES (?, ?, ?, ?, ?)")
+	if s.fts {
+		prepare(&st.fts, "INSERT INTO jmdict_glosses_fts (rowid, definition) VALUES (?, ?)")
+	}
+	return &st, err
+}
+
+// importEntries imports the entries in r into the store's empty tables
+func (s *Store) importEntries(r io.Reader, size int64, progress dictionary.Progress) error {
+	b := &batch{s: s}
+	var (
+		id        int64
+		insertErr error
+	)
+	err := dictionary.DecodeEntries(r, size, progress, func(entry *jmdict.Entry) {
+		id++
+		if insertErr == nil {
+			insertErr = b.insert(id, entry)
+		}
+	})
+	if err == nil {
+		err = insertErr
+	}
+	if err == nil {
+		err = b.commit()
+	}
+	if err != nil {
+		b.rollback()
+	}
+	return err
+}
+
+// batch inserts entries in transactions of importBatchSize entries
+type batch struct {
+	s  *Store
+	tx *sql.Tx
+	st *statements
+	n  int
+}
+
+func (b *batch) insert(id int64, entry *jmdict.Entry) error {
+	if b.tx == nil {
+		tx, err := b.s.db.Begin()
+		if err != nil {
+			return err
+		}
+		st, err := b.s.prepareStatements(tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		b.tx, b.st = tx, st
+	}
+	if err := b.st.insert(id, entry); err != nil {
+		return err
+	}
+	if b.n++; b.n == importBatchSize {
+		return b.commit()
+	}
+	return nil
+}
+
+// commit commits the entries inserted since the last commit
+func (b *batch) commit() error {
+	if b.tx == nil {
+		return nil
+	}
+	err := b.tx.Commit()
+	b.tx, b.n = nil, 0
+	return err
+}
+
+// rollback abandons the entries inserted since the last commit
+func (b *batch) rollback() {
+	if b.tx != nil {
+		b.tx.Rollback()
+		b.tx, b.n = nil, 0
+	}
+}
+
+// insert inserts an entry into each table
+func (st *statements) insert(id int64, entry *jmdict.Entry) error {
+	var err error
+	exec := func(stmt *sql.Stmt, args ...interface{}) sql.Result {
+		if err != nil {
+			return nil
+		}
+		var result sql.Result
+		result, err = stmt.Exec(args...)
+		return result
+	}
+	tags := func(element string, position int, kind string, values []string) {
+		for _, value := range values {
+			exec(st.tag, id, element, position, kind, value)
+		}
+	}
+
+	exec(st.entry, id, entry.EntryID, int64(dictionary.Digest(entry)))
+	for i, k := range entry.KanjiElements {
+		exec(st.kanji, id, i, k.Phrase, kana.Normalize(k.Phrase))
+		tags(kanjiElement, i, infoTag, k.Info)
+		tags(kanjiElement, i, priorityTag, k.Priorities)
+	}
+	for i, r := range entry.ReadingElements {
+		exec(st.reading, id, i, r.Phrase, r.PhraseNoKanji, kana.Normalize(r.Phrase))
+		tags(readingElement, i, infoTag, r.Info)
+		tags(readingElement, i, priorityTag, r.Priorities)
+	}
+	for i, sense := range entry.Senses {
+		exec(st.sense, id, i)
+		tags(senseElement, i, posTag, sense.POS)
+		tags(senseElement, i, fieldTag, sense.Fields)
+		tags(senseElement, i, miscTag, sense.Misc)
+		tags(senseElement, i, dialectTag, sense.Dialects)
+		tags(senseElement, i, referenceTag, sense.CrossReferences)
+		tags(senseElement, i, antonymTag, sense.Antonyms)
+		for _, source := range sense.SourceLanguages {
+			exec(st.loanword, id, i, source.Language, source.Type, source.Wasei)
+		}
+		for _, gloss := range sense.GlossaryItems {
+			result := exec(st.gloss, id, i, gloss.Language, gloss.Gender, gloss.Type, gloss.Definition)
+			if st.fts != nil && err == nil {
+				var glossID int64
+				if glossID, err = result.LastInsertId(); err == nil {
+					exec(st.fts, glossID, gloss.Definition)
+				}
+			}
+		}
+	}
+	return err
+}
+
+// Info returns the metadata of the store
+func (s *Store) Info() dictionary.SourceInfo {
+	return s.info
+}
+
+// placeholders returns n comma separated placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// idList formats entry IDs for a query, since they're always integers
+func idList(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
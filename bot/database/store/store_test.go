@@ -0,0 +1,305 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/config"
+	"github.com/hakasec/japanbot-go/bot/database"
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+const testJMdict = `<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>日本</keb><ke_pri>news1</ke_pri></k_ele><r_ele><reb>にほん</reb></r_ele><sense><pos>n</pos><gloss>Japan</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>日本語</keb></k_ele><r_ele><reb>にほんご</reb></r_ele><sense><pos>n</pos><gloss>Japanese (language)</gloss></sense></entry>
+<entry><ent_seq>3</ent_seq><k_ele><keb>行く</keb><ke_pri>ichi1</ke_pri></k_ele><r_ele><reb>いく</reb></r_ele><sense><pos>v5k-s</pos><xref>来る</xref><gloss>to go</gloss><gloss>to move (towards)</gloss></sense><sense><misc>col</misc><gloss>to proceed</gloss></sense></entry>
+<entry><ent_seq>4</ent_seq><k_ele><keb>碁</keb></k_ele><r_ele><reb>ご</reb></r_ele><sense><pos>n</pos><gloss>go (board game)</gloss></sense></entry>
+<entry><ent_seq>5</ent_seq><r_ele><reb>パン</reb></r_ele><sense><pos>n</pos><lsource xml:lang="por">pão</lsource><gloss>bread</gloss><gloss xml:lang="ger">Brot</gloss></sense></entry>
+<entry><ent_seq>6</ent_seq><k_ele><keb>以前</keb></k_ele><r_ele><reb>いぜん</reb></r_ele><sense><pos>n</pos><gloss>ago</gloss><gloss>good while back</gloss></sense></entry>
+<entry><ent_seq>7</ent_seq><k_ele><keb>勉強</keb><ke_pri>ichi1</ke_pri></k_ele><r_ele><reb>べんきょう</reb><re_pri>ichi1</re_pri></r_ele><sense><pos>n</pos><pos>vs</pos><gloss>study</gloss></sense></entry>
+<entry><ent_seq>8</ent_seq><k_ele><keb>日曜日</keb><ke_pri>news1</ke_pri><ke_pri>nf01</ke_pri></k_ele><r_ele><reb>にちようび</reb></r_ele><sense><pos>n</pos><gloss>Sunday</gloss></sense></entry>
+</JMdict>`
+
+// openTestStore imports testJMdict into an in-memory database, returning
+// the store and the same file loaded as a Dictionary
+func openTestStore(t *testing.T) (*Store, *dictionary.Dictionary) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := importTestFile(t, openTestDB(t), filepath.Join(dir, "JMdict"), testJMdict)
+	d, err := dictionary.Load(strings.NewReader(testJMdict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, d
+}
+
+func openTestDB(t *testing.T) *database.DBConnection {
+	db, err := database.OpenFromConfig(&config.DBConfiguration{
+		DriverName: "sqlite3",
+		ConnString: ":memory:",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+// importTestFile writes a JMdict file and imports it with a new store
+func importTestFile(t *testing.T, db *database.DBConnection, file, contents string) *Store {
+	if err := ioutil.WriteFile(file, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := New(db)
+	if err := s.CreateTables(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Import(file, nil); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestLookup(t *testing.T) {
+	s, d := openTestStore(t)
+
+	for _, key := range []string{"日本語", "いく", "ぱん", "nihon"} {
+		if got, want := s.Lookup(key), d.Lookup(key); len(got) == 0 || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %+v, got %+v", key, want, got)
+		}
+	}
+	if entries := s.Lookup("猫"); len(entries) != 0 {
+		t.Errorf("expected no entries, got %+v", entries)
+	}
+	if got, want := s.LookupID("3"), d.LookupID("3"); len(got) != 1 || !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestMatchPrefixes(t *testing.T) {
+	s, d := openTestStore(t)
+
+	for _, text := range []string{"日本語を", "パンを", "ぱん", "碁"} {
+		if got, want := s.MatchPrefixes(text), d.MatchPrefixes(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", text, want, got)
+		}
+	}
+}
+
+func TestOutline(t *testing.T) {
+	s, d := openTestStore(t)
+
+	outlines := s.lookup("行く", true)
+	if len(outlines) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", outlines)
+	}
+	iku := outlines[0]
+	if iku.KanjiElements[0].Priorities[0] != "ichi1" || iku.Senses[0].POS[0] != "v5k-s" {
+		t.Errorf("expected priorities and parts of speech, got %+v", iku)
+	}
+	if len(iku.Senses) != 2 || len(iku.Senses[0].GlossaryItems) != 0 || len(iku.Senses[1].Misc) != 0 {
+		t.Errorf("expected senses without glosses or other tags, got %+v", iku.Senses)
+	}
+
+	for _, key := range []string{"日本", "行く", "いく", "碁", "猫"} {
+		if got, want := s.KeyPriority(key), d.KeyPriority(key); got != want {
+			t.Errorf("%s: expected priority %d, got %d", key, want, got)
+		}
+		if got, want := s.IsCommonKey(key), d.IsCommonKey(key); got != want {
+			t.Errorf("%s: expected common %t, got %t", key, want, got)
+		}
+		if got, want := s.Readings(key), d.Readings(key); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected readings %v, got %v", key, want, got)
+		}
+		if got, want := s.Contains(key), d.Contains(key); got != want {
+			t.Errorf("%s: expected contains %t, got %t", key, want, got)
+		}
+	}
+}
+
+func TestSegment(t *testing.T) {
+	s, d := openTestStore(t)
+
+	for _, phrase := range []string{"日本語を勉強しました", "行きませんでした", "benkyou shita", "パンと碁"} {
+		if got, want := s.Segment(phrase), d.Segment(phrase); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %+v, got %+v", phrase, want, got)
+		}
+		if got, want := s.SegmentText(phrase), d.SegmentText(phrase); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected text %+v, got %+v", phrase, want, got)
+		}
+	}
+	if got, want := s.Deinflect("いかない"), d.Deinflect("いかない"); len(got) == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s, d := openTestStore(t)
+
+	for _, pattern := range []string{"日*", "*本*", "?本", "[reading]に*", "日本", "[reading]日本", "猫*"} {
+		for _, page := range []int{0, 1} {
+			got, want := s.Search(pattern, page, 1), d.Search(pattern, page, 1)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s page %d: expected %+v, got %+v", pattern, page, want, got)
+			}
+		}
+	}
+}
+
+func TestCommonWordsContaining(t *testing.T) {
+	s, d := openTestStore(t)
+
+	for _, character := range []string{"日", "強", "猫"} {
+		got, want := s.CommonWordsContaining(character, 5), d.CommonWordsContaining(character, 5)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: expected %v, got %v", character, want, got)
+		}
+	}
+}
+
+func TestRandomEntry(t *testing.T) {
+	s, d := openTestStore(t)
+
+	entry := s.RandomEntry()
+	if entry == nil || len(entry.Senses[0].GlossaryItems) == 0 {
+		t.Fatalf("expected an entry with glosses, got %+v", entry)
+	}
+	if want := d.LookupID(entry.EntryID); !reflect.DeepEqual([]*jmdict.Entry{entry}, want) {
+		t.Errorf("expected %+v, got %+v", want, entry)
+	}
+}
+
+func TestImportStamp(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	var stamp string
+	if err := s.db.QueryRow("SELECT imported FROM jmdict_sets WHERE active = 1").Scan(&stamp); err != nil {
+		t.Fatal(err)
+	}
+	// the stamp changes when full text search becomes available
+	if !strings.HasSuffix(stamp, fmt.Sprintf(" %t", s.fts)) {
+		t.Errorf("expected the stamp %q to record full text search as %t", stamp, s.fts)
+	}
+}
+
+func TestImportAgain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "JMdict")
+	db := openTestDB(t)
+
+	old := importTestFile(t, db, file, testJMdict)
+	updated := strings.Replace(
+		testJMdict,
+		"</JMdict>",
+		"<entry><ent_seq>9</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><gloss>cat</gloss></sense></entry>\n</JMdict>",
+		1,
+	)
+	s := importTestFile(t, db, file, updated)
+	if s.set == old.set {
+		t.Fatalf("expected the new file to be imported into the other set of tables")
+	}
+
+	// the old store keeps reading the old entries until it's replaced
+	if entries := old.Lookup("猫"); len(entries) != 0 {
+		t.Errorf("expected the old store to be unchanged, got %+v", entries)
+	}
+	if entries := s.Lookup("猫"); len(entries) != 1 || s.SearchGloss("cat", 0)[0].Entry.EntryID != "9" {
+		t.Errorf("expected 猫 in the new store, got %+v", entries)
+	}
+
+	// the next store reads the new set without importing the file again
+	if next := importTestFile(t, db, file, updated); next.set != s.set {
+		t.Errorf("expected set %s, got %s", s.set, next.set)
+	}
+}
+
+func TestSearchGloss(t *testing.T) {
+	s, d := openTestStore(t)
+
+	search := func(t *testing.T) {
+		for _, query := range []string{"go", "japan", "bread", "brot", "good while", "language"} {
+			got, want := s.SearchGloss(query, 0), d.SearchGloss(query, 0)
+			if len(got) != len(want) {
+				t.Errorf("%s: expected %d matches, got %d", query, len(want), len(got))
+				continue
+			}
+			for i := range got {
+				if got[i].Entry.EntryID != want[i].Entry.EntryID || got[i].Gloss != want[i].Gloss ||
+					got[i].Exact != want[i].Exact || got[i].Score != want[i].Score {
+					t.Errorf("%s: match %d: expected %+v, got %+v", query, i, want[i], got[i])
+				}
+			}
+		}
+	}
+	if s.fts {
+		t.Run("fts", search)
+	}
+	s.fts = false
+	t.Run("scan", search)
+}
+
+func TestCompare(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "JMdict")
+	db := openTestDB(t)
+
+	updated := strings.NewReplacer(
+		// 碁 is removed, パン has a new gloss and 行く only a new part of speech
+		`<entry><ent_seq>4</ent_seq><k_ele><keb>碁</keb></k_ele><r_ele><reb>ご</reb></r_ele><sense><pos>n</pos><gloss>go (board game)</gloss></sense></entry>`, "",
+		"<gloss>bread</gloss>", "<gloss>bread (food)</gloss>",
+		"<pos>v5k-s</pos>", "<pos>v5k-s</pos><pos>vi</pos>",
+		"</JMdict>", "<entry><ent_seq>9</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><gloss>cat</gloss></sense></entry>\n</JMdict>",
+	).Replace(testJMdict)
+
+	// a reload imports the new file next to the old one
+	before := importTestFile(t, db, file, testJMdict)
+	after := importTestFile(t, db, file, updated)
+	diff, err := after.Compare(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := dictionary.Load(strings.NewReader(testJMdict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	updatedDictionary, err := dictionary.Load(strings.NewReader(updated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dictionary.Compare(d, updatedDictionary)
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("expected %+v, got %+v", want, diff)
+	}
+	if len(diff.Modified) != 1 || strings.Join(diff.Modified[0].AddedGlosses, ";") != "bread (food)" {
+		t.Errorf("expected the new gloss of パン, got %+v", diff.Modified)
+	}
+	if len(diff.Added) != 1 || len(diff.Added[0].Senses[0].GlossaryItems) != 1 {
+		t.Errorf("expected 猫 to be added with its gloss, got %+v", diff.Added)
+	}
+
+	// reloading the same file changes nothing
+	if diff, err := after.Compare(after); err != nil || !diff.Empty() {
+		t.Errorf("expected no changes, got %+v, %v", diff, err)
+	}
+}
@@ -0,0 +1,256 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/inflection"
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// Deinflect finds the dictionary forms of an inflected word which have an
+// entry of a matching part of speech
+func (s *Store) Deinflect(word string) []dictionary.Deinflection {
+	return dictionary.DeinflectWith(word, s.keyTypes)
+}
+
+// keyTypes combines the inflection types of the entries for each key,
+// finding the entries of every key in one query. Keys are matched as
+// Lookup matches them
+func (s *Store) keyTypes(keys []string) map[string]inflection.Type {
+	var (
+		args  []interface{}
+		added = make(map[string]bool)
+	)
+	add := func(value string) {
+		if !added[value] {
+			added[value] = true
+			args = append(args, value)
+		}
+	}
+	for _, key := range keys {
+		add(key)
+		add(kana.Normalize(key))
+		if input := kana.FromInput(key); input != kana.FoldWidth(key) {
+			add(kana.Normalize(input))
+		}
+	}
+
+	numbered := make([]string, len(args))
+	for i := range args {
+		numbered[i] = fmt.Sprintf("?%d", i+1)
+	}
+	query := fmt.Sprintf(
+		"SELECT e.phrase, e.normalised, t.value FROM ("+
+			"SELECT entry, phrase, normalised FROM jmdict_kanji WHERE phrase IN (%[1]s) OR normalised IN (%[1]s) "+
+			"UNION SELECT entry, phrase, normalised FROM jmdict_readings WHERE phrase IN (%[1]s) OR normalised IN (%[1]s)"+
+			") e LEFT JOIN jmdict_tags t ON t.entry = e.entry AND t.kind = '%[2]s'",
+		strings.Join(numbered, ","),
+		posTag,
+	)
+
+	var (
+		phrases    = make(map[string]inflection.Type)
+		normalised = make(map[string]inflection.Type)
+	)
+	err := s.eachRow(query, func(rows *sql.Rows) error {
+		var (
+			phrase, normal string
+			pos            sql.NullString
+		)
+		if err := rows.Scan(&phrase, &normal, &pos); err != nil {
+			return err
+		}
+		var t inflection.Type
+		if pos.Valid {
+			t = inflection.TypeOf(dictionary.TagCode(pos.String))
+		}
+		// keys are recorded even if they don't inflect, since Lookup
+		// doesn't look any further for them
+		phrases[phrase] |= t
+		normalised[normal] |= t
+		return nil
+	}, args...)
+	if err != nil {
+		fmt.Printf("Error deinflecting: %s\n", err.Error())
+		return nil
+	}
+
+	types := make(map[string]inflection.Type, len(keys))
+	for _, key := range keys {
+		if t, ok := phrases[key]; ok {
+			types[key] = t
+		} else if t, ok := normalised[kana.Normalize(key)]; ok {
+			types[key] = t
+		} else if input := kana.FromInput(key); input != kana.FoldWidth(key) {
+			types[key] = normalised[kana.Normalize(input)]
+		}
+	}
+	return types
+}
+
+// Contains checks if a key is a kanji or reading exactly as written
+func (s *Store) Contains(key string) bool {
+	var found bool
+	err := s.db.QueryRow(
+		s.names.Replace(
+			"SELECT EXISTS (SELECT 1 FROM jmdict_kanji WHERE phrase = ?1) OR EXISTS (SELECT 1 FROM jmdict_readings WHERE phrase = ?1)",
+		),
+		key,
+	).Scan(&found)
+	if err != nil {
+		fmt.Printf("Error looking up entries: %s\n", err.Error())
+		return false
+	}
+	return found
+}
+
+// Segment splits a phrase into tokens as Dictionary.Segment does, looking
+// words up in the database
+func (s *Store) Segment(phrase string) []dictionary.Token {
+	return dictionary.SegmentWith(s, phrase)
+}
+
+// SegmentText is Segment for text which is shown as it was written
+func (s *Store) SegmentText(text string) []dictionary.Token {
+	return dictionary.SegmentTextWith(s, text)
+}
+
+// Readings lists the readings of the entries for a key, most likely first
+func (s *Store) Readings(key string) []string {
+	return dictionary.ReadingsOf(s.lookup(key, true))
+}
+
+// KeyPriority returns the highest priority of the entries for a key
+func (s *Store) KeyPriority(key string) int {
+	return dictionary.HighestPriority(s.lookup(key, true))
+}
+
+// IsCommonKey checks if any entry for a key is a common word
+func (s *Store) IsCommonKey(key string) bool {
+	return dictionary.AnyCommon(s.lookup(key, true))
+}
+
+// Search finds the kanji and readings matching a wildcard pattern as
+// Dictionary.Search does, in the order of the keys. The database finds
+// every match, so results are never truncated
+func (s *Store) Search(pattern string, offset, limit int) dictionary.SearchResult {
+	var result dictionary.SearchResult
+	pattern, readingOnly := dictionary.ParsePattern(pattern)
+	if pattern == "" {
+		return result
+	}
+
+	// * and ? mean the same in GLOB, but [ starts a set of characters
+	glob := strings.Replace(pattern, "[", "[[]", -1)
+	query := "SELECT phrase FROM jmdict_readings WHERE phrase GLOB ?1"
+	if !readingOnly {
+		query += " UNION SELECT phrase FROM jmdict_kanji WHERE phrase GLOB ?1"
+	}
+	query += " ORDER BY 1 LIMIT ?2 OFFSET ?3"
+
+	// one more than the page is found to see if there are more
+	err := s.eachRow(query, func(rows *sql.Rows) error {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return err
+		}
+		if len(result.Keys) == limit {
+			result.More = true
+		} else {
+			result.Keys = append(result.Keys, key)
+		}
+		return nil
+	}, glob, limit+1, offset)
+	if err != nil {
+		fmt.Printf("Error searching: %s\n", err.Error())
+		return dictionary.SearchResult{}
+	}
+	return result
+}
+
+// CommonWordsContaining finds the common kanji and readings which contain
+// a character, most common and then shortest first
+func (s *Store) CommonWordsContaining(character string, limit int) []string {
+	var (
+		ids     = []int64{}
+		keyIDs  = make(map[string][]int64)
+		entries = make(map[int64]bool)
+	)
+	err := s.eachRow(
+		"SELECT phrase, entry FROM jmdict_kanji WHERE instr(phrase, ?1) > 0 AND phrase != ?1 "+
+			"UNION SELECT phrase, entry FROM jmdict_readings WHERE instr(phrase, ?1) > 0 AND phrase != ?1 ORDER BY 2",
+		func(rows *sql.Rows) error {
+			var (
+				key string
+				id  int64
+			)
+			if err := rows.Scan(&key, &id); err != nil {
+				return err
+			}
+			keyIDs[key] = append(keyIDs[key], id)
+			if !entries[id] {
+				entries[id] = true
+				ids = append(ids, id)
+			}
+			return nil
+		},
+		character,
+	)
+	if err != nil {
+		fmt.Printf("Error finding words: %s\n", err.Error())
+		return nil
+	}
+	// outlines are loaded in the order of their IDs
+	outlines, err := s.loadEntries(ids, true)
+	if err == nil && len(outlines) != len(ids) {
+		err = fmt.Errorf("found %d of %d entries", len(outlines), len(ids))
+	}
+	if err != nil {
+		fmt.Printf("Error finding words: %s\n", err.Error())
+		return nil
+	}
+	byID := make(map[int64]*jmdict.Entry, len(ids))
+	for i, id := range ids {
+		byID[id] = outlines[i]
+	}
+
+	priorities := make(map[string]int)
+	for key, keyEntryIDs := range keyIDs {
+		keyEntries := make([]*jmdict.Entry, len(keyEntryIDs))
+		for i, id := range keyEntryIDs {
+			keyEntries[i] = byID[id]
+		}
+		if dictionary.AnyCommon(keyEntries) {
+			priorities[key] = dictionary.HighestPriority(keyEntries)
+		}
+	}
+	return dictionary.RankWords(priorities, limit)
+}
+
+// RandomEntry picks an entry at random, returning nil if there are none
+func (s *Store) RandomEntry() *jmdict.Entry {
+	var last sql.NullInt64
+	if err := s.db.QueryRow(s.names.Replace("SELECT max(id) FROM jmdict_entries")).Scan(&last); err != nil {
+		fmt.Printf("Error picking an entry: %s\n", err.Error())
+		return nil
+	}
+	if !last.Valid {
+		return nil
+	}
+	// entries are numbered from 1 as they're imported
+	entries, err := s.loadEntries([]int64{rand.Int63n(last.Int64) + 1}, false)
+	if err != nil {
+		fmt.Printf("Error picking an entry: %s\n", err.Error())
+		return nil
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return entries[0]
+}
@@ -0,0 +1,174 @@
+// Package datafile opens data files which may be compressed,
+// and recognises the dictionary files in a directory by their contents
+package datafile
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync/atomic"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	xzMagic   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+// File is a data file, decompressed as it is read if it is compressed
+type File struct {
+	f    *os.File
+	size int64
+	// raw counts the bytes read from the file on disk
+	raw *CountingReader
+	r   io.Reader
+
+	closers []func() error
+}
+
+// CountingReader counts the bytes read through it. The count can be read
+// while another goroutine reads, as os/exec does when it feeds xz
+type CountingReader struct {
+	r    io.Reader
+	read int64
+}
+
+// NewCountingReader counts the bytes read from r
+func NewCountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{r: r}
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.read, int64(n))
+	return n, err
+}
+
+// Position returns how many bytes have been read
+func (c *CountingReader) Position() int64 {
+	return atomic.LoadInt64(&c.read)
+}
+
+// Open opens a data file. Files compressed with gzip are decompressed
+// with compress/gzip and files compressed with xz with the xz program,
+// whatever their names
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+
+	file := &File{f: f, size: info.Size(), raw: NewCountingReader(f)}
+	file.closers = append(file.closers, f.Close)
+	buffered := bufio.NewReader(file.raw)
+	magic, _ := buffered.Peek(len(xzMagic))
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+		file.r = gz
+		file.closers = append(file.closers, gz.Close)
+	case bytes.HasPrefix(magic, xzMagic):
+		if err := file.startXZ(buffered); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+	default:
+		file.r = buffered
+	}
+	return file, nil
+}
+
+// startXZ decompresses the file with the xz program, since the
+// standard library can't
+func (file *File) startXZ(compressed io.Reader) error {
+	xzPath, err := exec.LookPath("xz")
+	if err != nil {
+		return errors.New("the xz program is needed to read xz files")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(xzPath, "--decompress", "--stdout")
+	cmd.Stdin = compressed
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	file.r = &xzReader{r: stdout, cmd: cmd, stderr: &stderr}
+	file.closers = append(file.closers, func() error {
+		stdout.Close()
+		// the process is killed if the file is closed before it's read to the end
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil
+	})
+	return nil
+}
+
+// xzReader reports the error of the xz program once its output ends
+type xzReader struct {
+	r      io.Reader
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+	done   bool
+}
+
+func (x *xzReader) Read(p []byte) (int, error) {
+	n, err := x.r.Read(p)
+	if err == io.EOF && !x.done {
+		x.done = true
+		if waitErr := x.cmd.Wait(); waitErr != nil {
+			return n, fmt.Errorf("xz: %s", bytes.TrimSpace(x.stderr.Bytes()))
+		}
+	}
+	return n, err
+}
+
+// Read reads the decompressed contents of the file
+func (file *File) Read(p []byte) (int, error) {
+	return file.r.Read(p)
+}
+
+// Size returns the size of the file on disk
+func (file *File) Size() int64 {
+	return file.size
+}
+
+// Position returns how many bytes of the file on disk have been read,
+// which can be compared to Size to see how far through it a reader is
+func (file *File) Position() int64 {
+	return file.raw.Position()
+}
+
+// Close closes the file
+func (file *File) Close() error {
+	var err error
+	for i := len(file.closers) - 1; i >= 0; i-- {
+		if closeErr := file.closers[i](); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
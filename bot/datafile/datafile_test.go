@@ -0,0 +1,130 @@
+package datafile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const testJMdict = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMdict [
+<!ENTITY n "noun (common) (futsuumeishi)">
+]>
+<JMdict>
+<entry><ent_seq>1</ent_seq><r_ele><reb>ねこ</reb></r_ele><sense><pos>&n;</pos><gloss>cat</gloss></sense></entry>
+</JMdict>`
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "datafile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func gzipped(t *testing.T, data string) []byte {
+	var buffer bytes.Buffer
+	w := gzip.NewWriter(&buffer)
+	w.Write([]byte(data))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func readAll(t *testing.T, path string) string {
+	f, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	// the position is read between reads, as the JMdict decoder does
+	// to report progress
+	var data bytes.Buffer
+	buffer := make([]byte, 16)
+	for {
+		n, err := f.Read(buffer)
+		data.Write(buffer[:n])
+		if f.Position() > f.Size() {
+			t.Fatalf("read %d bytes of a file of %d", f.Position(), f.Size())
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if f.Position() != f.Size() {
+		t.Errorf("expected to have read all %d bytes, read %d", f.Size(), f.Position())
+	}
+	return data.String()
+}
+
+func TestOpen(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	plain := filepath.Join(dir, "JMdict")
+	ioutil.WriteFile(plain, []byte(testJMdict), 0644)
+	if data := readAll(t, plain); data != testJMdict {
+		t.Errorf("expected the file as it is, got %q", data)
+	}
+
+	// detected by contents rather than name
+	compressed := filepath.Join(dir, "JMdict_e")
+	ioutil.WriteFile(compressed, gzipped(t, testJMdict), 0644)
+	if data := readAll(t, compressed); data != testJMdict {
+		t.Errorf("expected the gzip file to be decompressed, got %q", data)
+	}
+}
+
+func TestOpenXZ(t *testing.T) {
+	if _, err := exec.LookPath("xz"); err != nil {
+		t.Skip("xz isn't installed")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "JMdict.xz")
+	cmd := exec.Command("xz", "--compress", "--stdout")
+	cmd.Stdin = bytes.NewReader([]byte(testJMdict))
+	compressed, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(path, compressed, 0644)
+	if data := readAll(t, path); data != testJMdict {
+		t.Errorf("expected the xz file to be decompressed, got %q", data)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "a.gz"), gzipped(t, testJMdict), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "b.xml"), []byte("<JMnedict>\n</JMnedict>"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "c.xml"), []byte("<?xml version=\"1.0\"?>\n<kanjidic2>\n</kanjidic2>"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "d.txt"), []byte("日本\tにほん\t2\n"), 0644)
+	os.Mkdir(filepath.Join(dir, "kanji"), 0755)
+
+	files, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[Format]string{JMdict: "a.gz", JMnedict: "b.xml", Kanjidic2: "c.xml"}
+	if len(files) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	for format, name := range expected {
+		if files[format] != filepath.Join(dir, name) {
+			t.Errorf("expected %s to be %s, got %s", format, name, files[format])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package datafile
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+// Format is the format of a dictionary file
+type Format string
+
+// The formats Detect recognises, by the root elements of their XML
+const (
+	Unknown   Format = ""
+	JMdict    Format = "JMdict"
+	JMnedict  Format = "JMnedict"
+	Kanjidic2 Format = "kanjidic2"
+)
+
+const (
+	// maxDetectTokens is the most XML tokens read looking for the root element
+	maxDetectTokens = 64
+	// maxDetectBytes is the most of a file read looking for the root element,
+	// enough to get past the DTD at the start of each format
+	maxDetectBytes = 256 * 1024
+)
+
+// Detect finds the format of a dictionary file, which may be compressed
+func Detect(path string) (Format, error) {
+	f, err := Open(path)
+	if err != nil {
+		return Unknown, err
+	}
+	defer f.Close()
+
+	dec := xml.NewDecoder(io.LimitReader(f, maxDetectBytes))
+	dec.Strict = false
+	for i := 0; i < maxDetectTokens; i++ {
+		token, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			// it isn't XML
+			return Unknown, nil
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch format := Format(start.Name.Local); format {
+			case JMdict, JMnedict, Kanjidic2:
+				return format, nil
+			}
+			return Unknown, nil
+		}
+	}
+	return Unknown, nil
+}
+
+// Scan finds the dictionary files in a directory by their contents,
+// taking the first file of each format by name
+func Scan(dir string) (map[Format]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+
+	files := make(map[Format]string)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		format, err := Detect(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := files[format]; !ok && format != Unknown {
+			files[format] = path
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,70 @@
+package dictionary
+
+import (
+	"fmt"
+	"io"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/cache"
+	"github.com/hakasec/japanbot-go/bot/datafile"
+)
+
+// cacheVersion is changed whenever the decoded entries change,
+// such as when jmdict-go is updated, to rebuild old caches
+const cacheVersion = 2
+
+// cachedEntries is the contents of a dictionary cache
+type cachedEntries struct {
+	Entries []*jmdict.Entry
+}
+
+// Open loads a JMdict file, using its cache when it is fresh.
+// progress is called as the file is read if it isn't nil
+func Open(file string, progress Progress) (*Dictionary, error) {
+	return openCached(file, func(r io.Reader, size int64) (*Dictionary, error) {
+		return LoadProgress(r, size, progress)
+	})
+}
+
+// OpenCached decodes a dictionary file with load, or reads the entries
+// it decoded last time from the cache next to the file if the file
+// hasn't changed since
+func OpenCached(file string, load func(r io.Reader) (*jmdict.JMdict, error)) (*Dictionary, error) {
+	return openCached(file, func(r io.Reader, size int64) (*Dictionary, error) {
+		j, err := load(r)
+		if err != nil {
+			return nil, err
+		}
+		return New(j), nil
+	})
+}
+
+// openCached creates a dictionary from the cache next to file, or with
+// build, rewriting the cache whenever the file is decoded. The file
+// may be compressed
+func openCached(file string, build func(r io.Reader, size int64) (*Dictionary, error)) (*Dictionary, error) {
+	path := cache.Path(file)
+	var cached cachedEntries
+	err := cache.Read(path, cacheVersion, &cached, file)
+	if err == nil {
+		return fromEntries(cached.Entries), nil
+	} else if err != cache.ErrStale {
+		fmt.Printf("Rebuilding dictionary cache: %s\n", err.Error())
+	}
+
+	r, err := datafile.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	d, err := build(r, r.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err.Error())
+	}
+	if err := cache.Write(path, cacheVersion, cachedEntries{Entries: d.entries}, file); err != nil {
+		fmt.Printf("Error writing dictionary cache: %s\n", err.Error())
+	}
+	return d, nil
+}
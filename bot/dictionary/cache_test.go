@@ -0,0 +1,59 @@
+package dictionary
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/cache"
+)
+
+func TestOpenCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dictionary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "JMdict")
+	if err := ioutil.WriteFile(file, []byte(testJMdict), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Open(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Lookup("日本語")) != 1 {
+		t.Fatalf("expected 日本語, got %v", d.Lookup("日本語"))
+	}
+
+	// a fresh cache is read instead of the file
+	cached := cachedEntries{Entries: []*jmdict.Entry{{
+		EntryID:         "9",
+		ReadingElements: []jmdict.ReadingElement{{Phrase: "ねこ"}},
+	}}}
+	if err := cache.Write(cache.Path(file), cacheVersion, cached, file); err != nil {
+		t.Fatal(err)
+	}
+	if d, err = Open(file, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Lookup("ねこ")) != 1 || len(d.Lookup("日本語")) != 0 {
+		t.Errorf("expected the cached entries, got %v", d.Entries())
+	}
+
+	// changing the file rebuilds the cache
+	if err := ioutil.WriteFile(file, []byte(testInflectedJMdict), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if d, err = Open(file, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Lookup("食べる")) != 1 {
+		t.Errorf("expected the changed file to be decoded, got %v", d.Entries())
+	}
+}
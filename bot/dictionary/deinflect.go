@@ -0,0 +1,82 @@
+package dictionary
+
+import (
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/inflection"
+)
+
+// Deinflection is a dictionary form found by deinflecting a word
+type Deinflection struct {
+	// Key is the Index key of the dictionary form
+	Key string
+	// Reasons lists the inflections applied, innermost first
+	Reasons []string
+}
+
+// Deinflect finds the dictionary forms of an inflected word which have an
+// entry of a matching part of speech
+func (d *Dictionary) Deinflect(word string) []Deinflection {
+	return DeinflectWith(word, func(keys []string) map[string]inflection.Type {
+		types := make(map[string]inflection.Type, len(keys))
+		for _, key := range keys {
+			for _, entry := range d.Lookup(key) {
+				types[key] |= EntryType(entry)
+			}
+		}
+		return types
+	})
+}
+
+// DeinflectWith is Deinflect for any dictionary, where keyTypes combines
+// the inflection types of the entries for each of keys
+func DeinflectWith(word string, keyTypes func(keys []string) map[string]inflection.Type) []Deinflection {
+	candidates := inflection.Deinflect(word)
+	if len(candidates) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		keys = append(keys, c.Word)
+		if stem := suruStem(c); stem != "" {
+			keys = append(keys, stem)
+		}
+	}
+	types := keyTypes(keys)
+
+	var result []Deinflection
+	for _, c := range candidates {
+		key := c.Word
+		valid := types[key]&c.Type != 0
+		// nouns which take suru are listed without it
+		if stem := suruStem(c); !valid && stem != "" {
+			key = stem
+			valid = types[key]&inflection.TypeSuru != 0
+		}
+		if valid {
+			result = append(result, Deinflection{Key: key, Reasons: c.Reasons})
+		}
+	}
+	return result
+}
+
+// suruStem returns the noun a suru candidate is formed from, if it has one
+func suruStem(c inflection.Candidate) string {
+	if c.Type&inflection.TypeSuru == 0 || !strings.HasSuffix(c.Word, "する") {
+		return ""
+	}
+	return strings.TrimSuffix(c.Word, "する")
+}
+
+// EntryType combines the inflection types of every sense of an entry
+func EntryType(entry *jmdict.Entry) inflection.Type {
+	var t inflection.Type
+	for _, sense := range entry.Senses {
+		for _, pos := range sense.POS {
+			t |= inflection.TypeOf(TagCode(pos))
+		}
+	}
+	return t
+}
@@ -10,44 +10,86 @@ type Dictionary struct {
 	Index     map[string][]*jmdict.Entry
 	IndexByID map[string][]*jmdict.Entry
 
-	*jmdict.JMdict
-}
-
-func (d *Dictionary) createIndex() {
-	for i, entry := range d.Entries {
-		for _, kanji := range entry.KanjiElements {
-			if _, ok := d.Index[kanji.Phrase]; !ok {
-				d.Index[kanji.Phrase] = make([]*jmdict.Entry, 0)
-			}
-			d.Index[kanji.Phrase] = append(d.Index[kanji.Phrase], &d.Entries[i])
-		}
-		for _, reading := range entry.ReadingElements {
-			if _, ok := d.Index[reading.Phrase]; !ok {
-				d.Index[reading.Phrase] = make([]*jmdict.Entry, 0)
-			}
-			if _, ok := d.Index[reading.PhraseNoKanji]; !ok {
-				d.Index[reading.PhraseNoKanji] = make([]*jmdict.Entry, 0)
-			}
-			d.Index[reading.Phrase] = append(d.Index[reading.Phrase], &d.Entries[i])
-			d.Index[reading.PhraseNoKanji] = append(d.Index[reading.PhraseNoKanji], &d.Entries[i])
-		}
-		if _, ok := d.IndexByID[entry.EntryID]; !ok {
-			d.IndexByID[entry.EntryID] = append(d.IndexByID[entry.EntryID], &d.Entries[i])
-		}
+	// entries holds every entry in the order they were added
+	entries []*jmdict.Entry
+	// normalIndex holds entries by their normalised keys,
+	// where these differ from the keys in Index
+	normalIndex map[string][]*jmdict.Entry
+	trie        *Trie
+	glossIndex  map[string][]glossPosting
+	search      *searchIndex
+	info        SourceInfo
+}
+
+func newDictionary() *Dictionary {
+	return &Dictionary{
+		Index:       make(map[string][]*jmdict.Entry),
+		IndexByID:   make(map[string][]*jmdict.Entry),
+		normalIndex: make(map[string][]*jmdict.Entry),
+		trie:        NewTrie(),
+		glossIndex:  make(map[string][]glossPosting),
+		info:        SourceInfo{Name: "JMdict"},
+	}
+}
+
+// add adds an entry to the dictionary and indexes it
+func (d *Dictionary) add(entry *jmdict.Entry) {
+	d.entries = append(d.entries, entry)
+	for _, kanji := range entry.KanjiElements {
+		d.Index[kanji.Phrase] = append(d.Index[kanji.Phrase], entry)
+		d.indexNormalised(kanji.Phrase, entry)
 	}
+	for _, reading := range entry.ReadingElements {
+		d.Index[reading.Phrase] = append(d.Index[reading.Phrase], entry)
+		d.Index[reading.PhraseNoKanji] = append(d.Index[reading.PhraseNoKanji], entry)
+		d.indexNormalised(reading.Phrase, entry)
+		d.indexNormalised(reading.PhraseNoKanji, entry)
+	}
+	d.indexGlosses(entry)
+	if _, ok := d.IndexByID[entry.EntryID]; !ok {
+		d.IndexByID[entry.EntryID] = append(d.IndexByID[entry.EntryID], entry)
+	}
+}
+
+// Entries returns every entry in the dictionary
+func (d *Dictionary) Entries() []*jmdict.Entry {
+	return d.entries
 }
 
+// Load decodes a JMdict document, indexing each entry as it is read
 func Load(r io.Reader) (*Dictionary, error) {
-	var err error
-	d := &Dictionary{}
-	d.JMdict, err = jmdict.Load(r)
-	if err != nil {
+	return LoadProgress(r, 0, nil)
+}
+
+// LoadProgress is Load for a document of size bytes,
+// calling progress as it is read if it isn't nil
+func LoadProgress(r io.Reader, size int64, progress Progress) (*Dictionary, error) {
+	d := newDictionary()
+	if err := DecodeEntries(r, size, progress, d.add); err != nil {
 		return nil, err
 	}
+	d.createSearchIndex()
+	return d, nil
+}
 
-	d.Index = make(map[string][]*jmdict.Entry)
-	d.IndexByID = make(map[string][]*jmdict.Entry)
-	d.createIndex()
+// New creates a dictionary from decoded entries, such as those of another
+// dictionary converted to the JMdict format
+func New(j *jmdict.JMdict) *Dictionary {
+	entries := make([]*jmdict.Entry, len(j.Entries))
+	for i := range j.Entries {
+		entries[i] = &j.Entries[i]
+	}
+	return fromEntries(entries)
+}
 
-	return d, nil
+// fromEntries creates a dictionary from entries, sharing their repeated strings
+func fromEntries(entries []*jmdict.Entry) *Dictionary {
+	d := newDictionary()
+	shared := make(interner)
+	for _, entry := range entries {
+		shared.entry(entry)
+		d.add(entry)
+	}
+	d.createSearchIndex()
+	return d
 }
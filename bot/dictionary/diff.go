@@ -0,0 +1,204 @@
+package dictionary
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+// Diff lists the entries which changed between two releases of a dictionary
+type Diff struct {
+	Added    []*jmdict.Entry
+	Removed  []*jmdict.Entry
+	Modified []Modification
+}
+
+// Modification is an entry in both releases which has changed
+type Modification struct {
+	Old, New *jmdict.Entry
+	// AddedKanji and AddedReadings are the forms the old entry didn't have
+	AddedKanji    []string
+	AddedReadings []string
+	// AddedGlosses and RemovedGlosses are the glosses which changed
+	AddedGlosses   []string
+	RemovedGlosses []string
+	// AddedPriorities are the priority tags the old entry didn't have
+	AddedPriorities []string
+}
+
+// Compare compares two releases of a dictionary by their entry IDs. Entries
+// are only modified if their kanji, readings, glosses or priorities changed
+func Compare(before, after *Dictionary) *Diff {
+	diff := &Diff{}
+	for _, entry := range after.entries {
+		old, ok := before.IndexByID[entry.EntryID]
+		if !ok {
+			diff.Added = append(diff.Added, entry)
+		} else if m, ok := CompareEntries(old[0], entry); ok {
+			diff.Modified = append(diff.Modified, m)
+		}
+	}
+	for _, entry := range before.entries {
+		if _, ok := after.IndexByID[entry.EntryID]; !ok {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+	return diff
+}
+
+// CompareEntries finds the changes to an entry, returning false if there
+// are none
+func CompareEntries(old, entry *jmdict.Entry) (Modification, bool) {
+	oldKanji, kanji := entryKanji(old), entryKanji(entry)
+	oldReadings, readings := entryReadings(old), entryReadings(entry)
+	oldGlosses, glosses := entryGlosses(old), entryGlosses(entry)
+	m := Modification{
+		Old:             old,
+		New:             entry,
+		AddedKanji:      missing(kanji, oldKanji),
+		AddedReadings:   missing(readings, oldReadings),
+		AddedGlosses:    missing(glosses, oldGlosses),
+		RemovedGlosses:  missing(oldGlosses, glosses),
+		AddedPriorities: missing(entryPriorities(entry), entryPriorities(old)),
+	}
+	changed := len(m.AddedKanji) > 0 || len(m.AddedReadings) > 0 ||
+		len(m.AddedGlosses) > 0 || len(m.RemovedGlosses) > 0 ||
+		len(m.AddedPriorities) > 0
+	return m, changed
+}
+
+// Digest summarises the kanji, readings, glosses and priorities of an entry,
+// which are all Compare looks at. Entries with the same digest are
+// unchanged, and those with different digests may have changed
+func Digest(entry *jmdict.Entry) uint64 {
+	h := fnv.New64a()
+	for _, values := range [][]string{
+		entryKanji(entry), entryReadings(entry), entryGlosses(entry), entryPriorities(entry),
+	} {
+		// the order of each part doesn't matter to Compare
+		values = missing(values, nil)
+		sort.Strings(values)
+		for _, value := range values {
+			io.WriteString(h, value)
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{1})
+	}
+	return h.Sum64()
+}
+
+func entryKanji(entry *jmdict.Entry) []string {
+	var kanji []string
+	for _, k := range entry.KanjiElements {
+		kanji = append(kanji, k.Phrase)
+	}
+	return kanji
+}
+
+func entryReadings(entry *jmdict.Entry) []string {
+	var readings []string
+	for _, r := range entry.ReadingElements {
+		readings = append(readings, r.Phrase)
+	}
+	return readings
+}
+
+func entryGlosses(entry *jmdict.Entry) []string {
+	var glosses []string
+	for _, sense := range entry.Senses {
+		for _, gloss := range sense.GlossaryItems {
+			glosses = append(glosses, gloss.Definition)
+		}
+	}
+	return glosses
+}
+
+func entryPriorities(entry *jmdict.Entry) []string {
+	var priorities []string
+	for _, k := range entry.KanjiElements {
+		priorities = append(priorities, k.Priorities...)
+	}
+	for _, r := range entry.ReadingElements {
+		priorities = append(priorities, r.Priorities...)
+	}
+	return priorities
+}
+
+// missing returns the values which aren't in from, once each in order
+func missing(values, from []string) []string {
+	seen := make(map[string]bool, len(from))
+	for _, value := range from {
+		seen[value] = true
+	}
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// Empty checks if nothing changed
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Write writes a report of the changes, listing at most limit entries of
+// each kind, or all of them if limit is 0
+func (d *Diff) Write(w io.Writer, limit int) error {
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf(
+		"%d added, %d removed, %d modified\n",
+		len(d.Added), len(d.Removed), len(d.Modified),
+	))
+
+	writeEntries := func(title string, entries []*jmdict.Entry) {
+		if len(entries) == 0 {
+			return
+		}
+		report.WriteString(fmt.Sprintf("\n%s:\n", title))
+		for i, entry := range entries {
+			if limit > 0 && i == limit {
+				report.WriteString(fmt.Sprintf("...and %d more\n", len(entries)-limit))
+				break
+			}
+			report.WriteString(fmt.Sprintf("%s (%s)", Headword(entry), entry.EntryID))
+			if glosses := entryGlosses(entry); len(glosses) > 0 {
+				report.WriteString(": " + glosses[0])
+			}
+			report.WriteString("\n")
+		}
+	}
+	writeEntries("Added", d.Added)
+	writeEntries("Removed", d.Removed)
+
+	if len(d.Modified) > 0 {
+		report.WriteString("\nModified:\n")
+	}
+	for i, m := range d.Modified {
+		if limit > 0 && i == limit {
+			report.WriteString(fmt.Sprintf("...and %d more\n", len(d.Modified)-limit))
+			break
+		}
+		report.WriteString(fmt.Sprintf("%s (%s)\n", Headword(m.New), m.New.EntryID))
+		writeChange := func(label string, values []string) {
+			if len(values) > 0 {
+				report.WriteString(fmt.Sprintf("  %s %s\n", label, strings.Join(values, "; ")))
+			}
+		}
+		writeChange("+kanji:", m.AddedKanji)
+		writeChange("+readings:", m.AddedReadings)
+		writeChange("+glosses:", m.AddedGlosses)
+		writeChange("-glosses:", m.RemovedGlosses)
+		writeChange("+priorities:", m.AddedPriorities)
+	}
+
+	_, err := io.WriteString(w, report.String())
+	return err
+}
@@ -0,0 +1,105 @@
+package dictionary
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadDiffDictionary(t *testing.T, entries string) *Dictionary {
+	d, err := Load(strings.NewReader("<JMdict>" + entries + "</JMdict>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestCompare(t *testing.T) {
+	before := loadDiffDictionary(t, `
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><gloss>cat</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>犬</keb></k_ele><r_ele><reb>いぬ</reb></r_ele><sense><gloss>dog</gloss></sense></entry>
+<entry><ent_seq>3</ent_seq><r_ele><reb>ぬるぽ</reb></r_ele><sense><gloss>null pointer</gloss></sense></entry>
+`)
+	after := loadDiffDictionary(t, `
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫</keb><ke_pri>ichi1</ke_pri></k_ele><r_ele><reb>ねこ</reb></r_ele><r_ele><reb>ネコ</reb></r_ele><sense><gloss>cat (animal)</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>犬</keb></k_ele><r_ele><reb>いぬ</reb></r_ele><sense><pos>n</pos><gloss>dog</gloss></sense></entry>
+<entry><ent_seq>4</ent_seq><k_ele><keb>鯖</keb></k_ele><r_ele><reb>さば</reb></r_ele><sense><gloss>Discord server</gloss></sense></entry>
+`)
+
+	diff := Compare(before, after)
+	if len(diff.Added) != 1 || diff.Added[0].EntryID != "4" {
+		t.Errorf("expected entry 4 to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].EntryID != "3" {
+		t.Errorf("expected entry 3 to be removed, got %+v", diff.Removed)
+	}
+	// a new part of speech alone isn't reported
+	if len(diff.Modified) != 1 {
+		t.Fatalf("expected 1 modified entry, got %d", len(diff.Modified))
+	}
+
+	m := diff.Modified[0]
+	if m.New.EntryID != "1" {
+		t.Errorf("expected entry 1 to be modified, got %s", m.New.EntryID)
+	}
+	for name, test := range map[string]struct{ got, want []string }{
+		"readings":   {m.AddedReadings, []string{"ネコ"}},
+		"glosses":    {m.AddedGlosses, []string{"cat (animal)"}},
+		"removed":    {m.RemovedGlosses, []string{"cat"}},
+		"priorities": {m.AddedPriorities, []string{"ichi1"}},
+	} {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("expected %s %v, got %v", name, test.want, test.got)
+		}
+	}
+	if len(m.AddedKanji) != 0 {
+		t.Errorf("expected no new kanji, got %v", m.AddedKanji)
+	}
+}
+
+func TestDiffWrite(t *testing.T) {
+	before := loadDiffDictionary(t, "")
+	after := loadDiffDictionary(t, `
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><gloss>cat</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>犬</keb></k_ele><r_ele><reb>いぬ</reb></r_ele><sense><gloss>dog</gloss></sense></entry>
+`)
+
+	diff := Compare(before, after)
+	if diff.Empty() {
+		t.Fatal("expected changes")
+	}
+	var report bytes.Buffer
+	if err := diff.Write(&report, 1); err != nil {
+		t.Fatal(err)
+	}
+	want := "2 added, 0 removed, 0 modified\n\nAdded:\n猫 (1): cat\n...and 1 more\n"
+	if report.String() != want {
+		t.Errorf("expected report %q, got %q", want, report.String())
+	}
+
+	if !Compare(after, after).Empty() {
+		t.Error("expected no changes comparing a dictionary with itself")
+	}
+}
+
+func TestDigest(t *testing.T) {
+	before := loadDiffDictionary(t, `
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><gloss>cat</gloss><gloss>feline</gloss></sense></entry>
+`)
+	reordered := loadDiffDictionary(t, `
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><pos>n</pos><gloss>feline</gloss></sense><sense><gloss>cat</gloss></sense></entry>
+`)
+	changed := loadDiffDictionary(t, `
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><gloss>cat (animal)</gloss><gloss>feline</gloss></sense></entry>
+`)
+
+	digest := Digest(before.Entries()[0])
+	// changes Compare doesn't report keep the digest
+	if Digest(reordered.Entries()[0]) != digest {
+		t.Error("expected the same digest for reordered glosses")
+	}
+	if Digest(changed.Entries()[0]) == digest {
+		t.Error("expected a different digest for a changed gloss")
+	}
+}
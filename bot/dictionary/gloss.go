@@ -0,0 +1,224 @@
+package dictionary
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// sensePenalty is subtracted from a match for each sense before the matched one
+const sensePenalty = 5
+
+// MetaGlossType is the type of glosses which describe a word rather than
+// translate it, such as the frequencies of Yomichan dictionaries. They're
+// shown with definitions but aren't searched
+const MetaGlossType = "meta"
+
+// glossPosting locates a gloss containing a word
+type glossPosting struct {
+	entry *jmdict.Entry
+	sense int
+	gloss int
+}
+
+// GlossMatch is an entry found by searching its glosses
+type GlossMatch struct {
+	Entry *jmdict.Entry
+	// Sense is the index of the matched sense
+	Sense int
+	Gloss string
+	// Exact is set when the whole gloss is the query,
+	// which ranks the match above any that aren't
+	Exact bool
+	Score int
+	// Source is the source the entry came from, set when searching Sources
+	Source SourceInfo
+}
+
+// indexGlosses adds the English glosses of an entry to the gloss index
+func (d *Dictionary) indexGlosses(entry *jmdict.Entry) {
+	for s, sense := range entry.Senses {
+		for g, gloss := range sense.GlossaryItems {
+			if (gloss.Language != "" && gloss.Language != "eng") || gloss.Type == MetaGlossType {
+				continue
+			}
+			posting := glossPosting{entry: entry, sense: s, gloss: g}
+			for _, word := range uniqueWords(TokeniseGloss(gloss.Definition)) {
+				d.glossIndex[word] = append(d.glossIndex[word], posting)
+			}
+		}
+	}
+}
+
+// SearchGloss finds entries with an English gloss containing every word of
+// the query. Matches are ranked by whether the whole gloss matches,
+// the position of the sense and the priority of the entry
+func (d *Dictionary) SearchGloss(query string, limit int) []GlossMatch {
+	q := ParseGlossQuery(query)
+	words := q.Words()
+	if len(words) == 0 {
+		return nil
+	}
+
+	// start from the rarest word, checking the others against each gloss
+	postings := d.glossIndex[words[0]]
+	for _, word := range words[1:] {
+		if p := d.glossIndex[word]; len(p) < len(postings) {
+			postings = p
+		}
+	}
+
+	var matches []GlossMatch
+	for _, p := range postings {
+		gloss := p.entry.Senses[p.sense].GlossaryItems[p.gloss].Definition
+		if score, ok := q.Score(p.entry, p.sense, gloss); ok {
+			matches = append(matches, GlossMatch{
+				Entry: p.entry,
+				Sense: p.sense,
+				Gloss: gloss,
+				Exact: q.Exact(gloss),
+				Score: score,
+			})
+		}
+	}
+	return BestGlossMatches(matches, limit)
+}
+
+// GlossQuery is a parsed search for English glosses
+type GlossQuery struct {
+	words      []string
+	normalised string
+}
+
+// ParseGlossQuery parses a search for English glosses
+func ParseGlossQuery(query string) GlossQuery {
+	query = kana.FoldWidth(query)
+	return GlossQuery{
+		words:      uniqueWords(TokeniseGloss(query)),
+		normalised: normaliseGloss(query),
+	}
+}
+
+// Words returns the distinct words of the query
+func (q GlossQuery) Words() []string {
+	return q.words
+}
+
+// Score scores a gloss of an entry's sense by the priority of the entry
+// and the position of the sense, reporting false if the gloss doesn't
+// contain every word of the query
+func (q GlossQuery) Score(entry *jmdict.Entry, sense int, gloss string) (int, bool) {
+	if len(q.words) == 0 || !containsAll(TokeniseGloss(gloss), q.words) {
+		return 0, false
+	}
+
+	return Priority(entry) - sense*sensePenalty, true
+}
+
+// Exact checks if a gloss is the whole query
+func (q GlossQuery) Exact(gloss string) bool {
+	return normaliseGloss(gloss) == q.normalised
+}
+
+// betterGlossMatch orders exact matches first, then higher scores
+func betterGlossMatch(a, b GlossMatch) bool {
+	if a.Exact != b.Exact {
+		return a.Exact
+	}
+	return a.Score > b.Score
+}
+
+// BestGlossMatches keeps the best scoring match of each entry,
+// returning up to limit matches with the highest scores first
+func BestGlossMatches(matches []GlossMatch, limit int) []GlossMatch {
+	best := make(map[*jmdict.Entry]GlossMatch)
+	for _, match := range matches {
+		if b, ok := best[match.Entry]; !ok || betterGlossMatch(match, b) {
+			best[match.Entry] = match
+		}
+	}
+
+	result := make([]GlossMatch, 0, len(best))
+	for _, match := range best {
+		result = append(result, match)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Exact != result[j].Exact || result[i].Score != result[j].Score {
+			return betterGlossMatch(result[i], result[j])
+		}
+		return result[i].Entry.EntryID < result[j].Entry.EntryID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// TokeniseGloss splits a gloss into lower case words,
+// ignoring any notes in parentheses
+func TokeniseGloss(gloss string) []string {
+	return strings.FieldsFunc(
+		strings.ToLower(stripParentheses(gloss)),
+		func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+		},
+	)
+}
+
+// normaliseGloss reduces a gloss to its words for exact comparison,
+// dropping the "to" which starts verb glosses
+func normaliseGloss(gloss string) string {
+	words := TokeniseGloss(gloss)
+	if len(words) > 1 && words[0] == "to" {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// stripParentheses removes text in parentheses, including nested ones
+func stripParentheses(s string) string {
+	var (
+		builder strings.Builder
+		depth   int
+	)
+	for _, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func uniqueWords(words []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(words))
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+func containsAll(haystack, needles []string) bool {
+	set := make(map[string]bool, len(haystack))
+	for _, w := range haystack {
+		set[w] = true
+	}
+	for _, w := range needles {
+		if !set[w] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package dictionary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchGloss(t *testing.T) {
+	d := loadTestDictionary(t)
+
+	matches := d.SearchGloss("japanese", 0)
+	if len(matches) != 1 || Headword(matches[0].Entry) != "日本語" {
+		t.Errorf("expected 日本語, got %v", matches)
+	}
+
+	// the note in parentheses is ignored
+	if matches := d.SearchGloss("language", 0); len(matches) != 0 {
+		t.Errorf("expected no matches, got %v", matches)
+	}
+
+	matches = d.SearchGloss("Do", 0)
+	if len(matches) != 1 || !matches[0].Exact {
+		t.Errorf("expected exact match for する, got %v", matches)
+	}
+}
+
+func TestSearchGlossExactFirst(t *testing.T) {
+	d, err := Load(strings.NewReader(`<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫舌</keb><ke_pri>news1</ke_pri><ke_pri>ichi1</ke_pri><ke_pri>spec1</ke_pri><ke_pri>nf01</ke_pri></k_ele><r_ele><reb>ねこじた</reb></r_ele><sense><gloss>cat tongue</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>舌</keb></k_ele><r_ele><reb>した</reb></r_ele><sense><gloss>tongue</gloss></sense></entry>
+</JMdict>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches := d.SearchGloss("tongue", 0)
+	if len(matches) != 2 || Headword(matches[0].Entry) != "舌" {
+		t.Errorf("expected the exact match 舌 first, got %v", matches)
+	}
+}
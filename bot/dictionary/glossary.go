@@ -0,0 +1,45 @@
+package dictionary
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+// LoadGlossary loads a custom glossary of tab separated lines giving a word,
+// its reading and its meanings separated by semicolons, e.g.
+// 鯖	さば	Discord server; guild
+// The reading can be left blank for words written in kana
+func LoadGlossary(r io.Reader) (*jmdict.JMdict, error) {
+	j := &jmdict.JMdict{}
+	err := eachLine(r, func(line string) error {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || fields[0] == "" || fields[2] == "" {
+			return errors.New("expected word, reading and meanings separated by tabs")
+		}
+
+		entry := jmdict.Entry{EntryID: "g" + strconv.Itoa(len(j.Entries)+1)}
+		if fields[1] == "" {
+			entry.ReadingElements = []jmdict.ReadingElement{{Phrase: fields[0]}}
+		} else {
+			entry.KanjiElements = []jmdict.KanjiElement{{Phrase: fields[0]}}
+			entry.ReadingElements = []jmdict.ReadingElement{{Phrase: fields[1]}}
+		}
+		sense := jmdict.Sense{}
+		for _, meaning := range strings.Split(fields[2], ";") {
+			if meaning = strings.TrimSpace(meaning); meaning != "" {
+				sense.GlossaryItems = append(sense.GlossaryItems, jmdict.Glossary{Definition: meaning})
+			}
+		}
+		entry.Senses = []jmdict.Sense{sense}
+		j.Entries = append(j.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
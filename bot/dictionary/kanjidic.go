@@ -0,0 +1,149 @@
+package dictionary
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kanjidic holds the characters of a KANJIDIC2 file
+type Kanjidic struct {
+	Characters map[string]*Kanji
+}
+
+// Kanji is a character element of KANJIDIC2
+type Kanji struct {
+	Literal      string         `xml:"literal"`
+	Grade        int            `xml:"misc>grade"`
+	StrokeCounts []int          `xml:"misc>stroke_count"`
+	Frequency    int            `xml:"misc>freq"`
+	JLPT         int            `xml:"misc>jlpt"`
+	Readings     []KanjiReading `xml:"reading_meaning>rmgroup>reading"`
+	Meanings     []KanjiMeaning `xml:"reading_meaning>rmgroup>meaning"`
+	Nanori       []string       `xml:"reading_meaning>nanori"`
+}
+
+// KanjiReading is a reading of a kanji, where Type is ja_on or ja_kun for
+// Japanese readings
+type KanjiReading struct {
+	Type  string `xml:"r_type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// KanjiMeaning is a meaning of a kanji, where a blank Language is English
+type KanjiMeaning struct {
+	Language string `xml:"m_lang,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// LoadKanjidic loads a KANJIDIC2 XML file, decoding one character at a time
+func LoadKanjidic(r io.Reader) (*Kanjidic, error) {
+	k := &Kanjidic{Characters: make(map[string]*Kanji)}
+	counter := newLineReader(r)
+	dec := xml.NewDecoder(counter)
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, lineError(err, dec, counter)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "character" {
+			continue
+		}
+		kanji := &Kanji{}
+		if err = dec.DecodeElement(kanji, &start); err != nil {
+			return nil, lineError(err, dec, counter)
+		}
+		k.Characters[kanji.Literal] = kanji
+	}
+	return k, nil
+}
+
+// OnReadings returns the on'yomi of the kanji in katakana
+func (k *Kanji) OnReadings() []string {
+	return k.readingsOfType("ja_on")
+}
+
+// KunReadings returns the kun'yomi of the kanji in hiragana,
+// with okurigana after a full stop
+func (k *Kanji) KunReadings() []string {
+	return k.readingsOfType("ja_kun")
+}
+
+func (k *Kanji) readingsOfType(readingType string) []string {
+	var readings []string
+	for _, reading := range k.Readings {
+		if reading.Type == readingType {
+			readings = append(readings, reading.Value)
+		}
+	}
+	return readings
+}
+
+// MeaningsIn returns the meanings of the kanji in a language,
+// where "en" or a blank code gives English
+func (k *Kanji) MeaningsIn(language string) []string {
+	if language == "en" {
+		language = ""
+	}
+	var meanings []string
+	for _, meaning := range k.Meanings {
+		if meaning.Language == language {
+			meanings = append(meanings, meaning.Value)
+		}
+	}
+	return meanings
+}
+
+// StrokeCount returns the accepted stroke count of the kanji,
+// any others being common miscounts
+func (k *Kanji) StrokeCount() int {
+	if len(k.StrokeCounts) == 0 {
+		return 0
+	}
+	return k.StrokeCounts[0]
+}
+
+// CommonWordsContaining finds the common words in Index which contain a
+// character, most common and then shortest first
+func (d *Dictionary) CommonWordsContaining(character string, limit int) []string {
+	priorities := make(map[string]int)
+	for key := range d.Index {
+		if key == character || !strings.Contains(key, character) {
+			continue
+		}
+		if d.IsCommonKey(key) {
+			priorities[key] = d.KeyPriority(key)
+		}
+	}
+	return RankWords(priorities, limit)
+}
+
+// RankWords orders words by their priorities, most common and then
+// shortest first, keeping at most limit of them
+func RankWords(priorities map[string]int, limit int) []string {
+	words := make([]string, 0, len(priorities))
+	for word := range priorities {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		pi, pj := priorities[words[i]], priorities[words[j]]
+		if pi != pj {
+			return pi > pj
+		}
+		li, lj := utf8.RuneCountInString(words[i]), utf8.RuneCountInString(words[j])
+		if li != lj {
+			return li < lj
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > limit {
+		words = words[:limit]
+	}
+	return words
+}
@@ -0,0 +1,71 @@
+package dictionary
+
+import (
+	"strings"
+	"testing"
+)
+
+const testKanjidic = `<?xml version="1.0" encoding="UTF-8"?>
+<kanjidic2>
+<header><file_version>4</file_version></header>
+<character>
+<literal>日</literal>
+<misc><grade>1</grade><stroke_count>4</stroke_count><freq>1</freq><jlpt>4</jlpt></misc>
+<reading_meaning>
+<rmgroup>
+<reading r_type="pinyin">ri4</reading>
+<reading r_type="ja_on">ニチ</reading>
+<reading r_type="ja_on">ジツ</reading>
+<reading r_type="ja_kun">ひ</reading>
+<reading r_type="ja_kun">-か</reading>
+<meaning>day</meaning>
+<meaning>sun</meaning>
+<meaning m_lang="fr">jour</meaning>
+</rmgroup>
+<nanori>あき</nanori>
+</reading_meaning>
+</character>
+</kanjidic2>`
+
+func TestLoadKanjidic(t *testing.T) {
+	k, err := LoadKanjidic(strings.NewReader(testKanjidic))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kanji, ok := k.Characters["日"]
+	if !ok {
+		t.Fatal("expected 日 to be loaded")
+	}
+	if kanji.StrokeCount() != 4 || kanji.Grade != 1 || kanji.Frequency != 1 || kanji.JLPT != 4 {
+		t.Errorf("unexpected misc values: %+v", kanji)
+	}
+	if on := kanji.OnReadings(); strings.Join(on, ",") != "ニチ,ジツ" {
+		t.Errorf("expected on readings ニチ,ジツ, got %v", on)
+	}
+	if kun := kanji.KunReadings(); strings.Join(kun, ",") != "ひ,-か" {
+		t.Errorf("expected kun readings ひ,-か, got %v", kun)
+	}
+	if meanings := kanji.MeaningsIn("en"); strings.Join(meanings, ",") != "day,sun" {
+		t.Errorf("expected meanings day,sun, got %v", meanings)
+	}
+	if len(kanji.Nanori) != 1 || kanji.Nanori[0] != "あき" {
+		t.Errorf("expected nanori あき, got %v", kanji.Nanori)
+	}
+}
+
+func TestCommonWordsContaining(t *testing.T) {
+	d, err := Load(strings.NewReader(`<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>日本</keb><ke_pri>ichi1</ke_pri></k_ele><r_ele><reb>にほん</reb></r_ele><sense><gloss>Japan</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>日曜日</keb><ke_pri>news1</ke_pri><ke_pri>nf01</ke_pri></k_ele><r_ele><reb>にちようび</reb></r_ele><sense><gloss>Sunday</gloss></sense></entry>
+<entry><ent_seq>3</ent_seq><k_ele><keb>日本語</keb></k_ele><r_ele><reb>にほんご</reb></r_ele><sense><gloss>Japanese (language)</gloss></sense></entry>
+</JMdict>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	words := d.CommonWordsContaining("日", 5)
+	if strings.Join(words, ",") != "日曜日,日本" {
+		t.Errorf("expected 日曜日,日本, got %v", words)
+	}
+}
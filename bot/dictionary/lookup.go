@@ -0,0 +1,59 @@
+package dictionary
+
+import (
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// indexNormalised adds the normalised form of a key to the trie,
+// and to normalIndex if it differs from the key
+func (d *Dictionary) indexNormalised(key string, entry *jmdict.Entry) {
+	normalised := kana.Normalize(key)
+	d.trie.Insert(normalised)
+	if normalised == key {
+		return
+	}
+
+	entries := d.normalIndex[normalised]
+	// an entry's keys are indexed together, so duplicates are always last
+	if len(entries) == 0 || entries[len(entries)-1] != entry {
+		d.normalIndex[normalised] = append(entries, entry)
+	}
+}
+
+// Lookup finds the entries for a key. If there isn't an exact match the key
+// is normalised, so words can be looked up in hiragana, katakana, half width
+// katakana or romaji
+func (d *Dictionary) Lookup(key string) []*jmdict.Entry {
+	if entries, ok := d.Index[key]; ok {
+		return entries
+	}
+	if entries := d.lookupNormalised(kana.Normalize(key)); len(entries) > 0 {
+		return entries
+	}
+	if input := kana.FromInput(key); input != kana.FoldWidth(key) {
+		return d.lookupNormalised(kana.Normalize(input))
+	}
+	return nil
+}
+
+func (d *Dictionary) lookupNormalised(normalised string) []*jmdict.Entry {
+	entries := d.Index[normalised]
+	for _, entry := range d.normalIndex[normalised] {
+		if !containsEntry(entries, entry) {
+			// copy before appending so Index isn't modified
+			entries = append(entries[:len(entries):len(entries)], entry)
+		}
+	}
+	return entries
+}
+
+func containsEntry(entries []*jmdict.Entry, entry *jmdict.Entry) bool {
+	for _, e := range entries {
+		if e == entry {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package dictionary
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+// priorityWeights scores the ke_pri and re_pri tags of JMdict
+var priorityWeights = map[string]int{
+	"news1": 20,
+	"ichi1": 20,
+	"spec1": 15,
+	"gai1":  15,
+	"news2": 10,
+	"ichi2": 10,
+	"spec2": 5,
+	"gai2":  5,
+}
+
+// Priority scores how common an entry is from the priority tags of its
+// kanji and reading elements, higher being more common
+func Priority(entry *jmdict.Entry) int {
+	best := 0
+	for _, k := range entry.KanjiElements {
+		if score := priorityScore(k.Priorities); score > best {
+			best = score
+		}
+	}
+	for _, r := range entry.ReadingElements {
+		if score := priorityScore(r.Priorities); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+func priorityScore(priorities []string) int {
+	score := 0
+	for _, p := range priorities {
+		if weight, ok := priorityWeights[p]; ok {
+			score += weight
+		} else if strings.HasPrefix(p, "nf") {
+			// nfXX is the word's frequency rank in blocks of 500, nf01 being the highest
+			if rank, err := strconv.Atoi(p[2:]); err == nil && rank <= 48 {
+				score += 49 - rank
+			}
+		}
+	}
+	return score
+}
+
+// Headword returns the text an entry is best known by,
+// its first kanji element or otherwise its first reading
+func Headword(entry *jmdict.Entry) string {
+	if len(entry.KanjiElements) > 0 {
+		return entry.KanjiElements[0].Phrase
+	}
+	if len(entry.ReadingElements) > 0 {
+		return entry.ReadingElements[0].Phrase
+	}
+	return ""
+}
+
+// commonPriorities are the priority tags which mark an entry as common in JMdict
+var commonPriorities = []string{"news1", "ichi1", "spec1", "spec2", "gai1"}
+
+// IsCommon checks if an entry is marked as a common word
+func IsCommon(entry *jmdict.Entry) bool {
+	for _, k := range entry.KanjiElements {
+		if hasCommonPriority(k.Priorities) {
+			return true
+		}
+	}
+	for _, r := range entry.ReadingElements {
+		if hasCommonPriority(r.Priorities) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCommonPriority(priorities []string) bool {
+	for _, p := range priorities {
+		for _, c := range commonPriorities {
+			if p == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SortByPriority returns a copy of entries with the most common first,
+// otherwise keeping their order in the dictionary
+func SortByPriority(entries []*jmdict.Entry) []*jmdict.Entry {
+	sorted := make([]*jmdict.Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return Priority(sorted[i]) > Priority(sorted[j])
+	})
+	return sorted
+}
+
+// KeyPriority returns the highest priority of the entries for a key
+func (d *Dictionary) KeyPriority(key string) int {
+	return HighestPriority(d.Lookup(key))
+}
+
+// IsCommonKey checks if any entry for a key is a common word
+func (d *Dictionary) IsCommonKey(key string) bool {
+	return AnyCommon(d.Lookup(key))
+}
+
+// HighestPriority returns the highest priority of entries
+func HighestPriority(entries []*jmdict.Entry) int {
+	best := 0
+	for _, entry := range entries {
+		if p := Priority(entry); p > best {
+			best = p
+		}
+	}
+	return best
+}
+
+// AnyCommon checks if any of entries is a common word
+func AnyCommon(entries []*jmdict.Entry) bool {
+	for _, entry := range entries {
+		if IsCommon(entry) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package dictionary
+
+import (
+	"testing"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+func TestSortByPriority(t *testing.T) {
+	rare := &jmdict.Entry{EntryID: "1"}
+	frequent := &jmdict.Entry{
+		EntryID: "2",
+		KanjiElements: []jmdict.KanjiElement{
+			{Phrase: "日", Priorities: []string{"ichi1", "nf01"}},
+		},
+	}
+	uncommon := &jmdict.Entry{
+		EntryID: "3",
+		ReadingElements: []jmdict.ReadingElement{
+			{Phrase: "ひ", Priorities: []string{"nf40"}},
+		},
+	}
+
+	sorted := SortByPriority([]*jmdict.Entry{rare, uncommon, frequent})
+	if sorted[0] != frequent || sorted[1] != uncommon || sorted[2] != rare {
+		t.Errorf("unexpected order %v", sorted)
+	}
+	if !IsCommon(frequent) || IsCommon(uncommon) {
+		t.Error("only ichi1 should be common")
+	}
+}
@@ -0,0 +1,155 @@
+package dictionary
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Radicals holds the components of kanji from KRADFILE and RADKFILE
+type Radicals struct {
+	// Components maps each kanji to the components it is made of
+	Components map[string][]string
+	// Kanji maps each component to the kanji containing it
+	Kanji map[string][]string
+	// Strokes holds the stroke count of each component
+	Strokes map[string]int
+}
+
+// componentAliases maps the usual form of a component to the character
+// KRADFILE and RADKFILE use in its place, as the usual form isn't in JIS X 0208
+var componentAliases = map[string]string{
+	"亻": "化", "刂": "刈", "⺌": "尚", "忄": "忙", "扌": "扎", "氵": "汁",
+	"犭": "犯", "艹": "艾", "辶": "辻", "阝": "阡", "礻": "礼", "衤": "初",
+	"灬": "杰", "𠆢": "个", "耂": "老",
+}
+
+// componentForms is the reverse of componentAliases
+var componentForms = map[string]string{}
+
+func init() {
+	for form, alias := range componentAliases {
+		componentForms[alias] = form
+	}
+}
+
+// ComponentAlias returns the character the radical files use for a component
+func ComponentAlias(component string) string {
+	if alias, ok := componentAliases[component]; ok {
+		return alias
+	}
+	return component
+}
+
+// ComponentForm returns the usual form of a component from the radical files
+func ComponentForm(component string) string {
+	if form, ok := componentForms[component]; ok {
+		return form
+	}
+	return component
+}
+
+// LoadRadicals loads KRADFILE and RADKFILE, which must be converted from
+// EUC-JP to UTF-8 first
+func LoadRadicals(kradfile, radkfile io.Reader) (*Radicals, error) {
+	r := &Radicals{
+		Components: make(map[string][]string),
+		Kanji:      make(map[string][]string),
+		Strokes:    make(map[string]int),
+	}
+	if err := r.loadKradfile(kradfile); err != nil {
+		return nil, fmt.Errorf("kradfile: %s", err.Error())
+	}
+	if err := r.loadRadkfile(radkfile); err != nil {
+		return nil, fmt.Errorf("radkfile: %s", err.Error())
+	}
+	return r, nil
+}
+
+// eachLine calls f with each line of r which isn't blank or a comment
+func eachLine(r io.Reader, f func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !utf8.ValidString(line) {
+			return fmt.Errorf("line %d isn't UTF-8, convert the file from EUC-JP first", lineNumber)
+		}
+		if err := f(line); err != nil {
+			return fmt.Errorf("line %d: %s", lineNumber, err.Error())
+		}
+	}
+	return scanner.Err()
+}
+
+// loadKradfile reads lines of the form 亜 : ｜ 一 口
+func (r *Radicals) loadKradfile(kradfile io.Reader) error {
+	return eachLine(kradfile, func(line string) error {
+		parts := strings.SplitN(line, " : ", 2)
+		if len(parts) != 2 {
+			return errors.New("expected kanji : components")
+		}
+		r.Components[parts[0]] = strings.Fields(parts[1])
+		return nil
+	})
+}
+
+// loadRadkfile reads sections starting with a line of the form $ 一 1,
+// giving the component and its stroke count, followed by lines of the
+// kanji containing it
+func (r *Radicals) loadRadkfile(radkfile io.Reader) error {
+	component := ""
+	return eachLine(radkfile, func(line string) error {
+		if strings.HasPrefix(line, "$") {
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return errors.New("expected $ component strokes")
+			}
+			strokes, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return err
+			}
+			component = fields[1]
+			r.Strokes[component] = strokes
+			return nil
+		}
+		if component == "" {
+			return errors.New("kanji listed before any component")
+		}
+		for _, kanji := range line {
+			r.Kanji[component] = append(r.Kanji[component], string(kanji))
+		}
+		return nil
+	})
+}
+
+// KanjiWith finds the kanji which contain every component given
+func (r *Radicals) KanjiWith(components []string) []string {
+	if len(components) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, component := range components {
+		for _, kanji := range r.Kanji[ComponentAlias(component)] {
+			counts[kanji]++
+		}
+	}
+	var result []string
+	for kanji, count := range counts {
+		if count == len(components) {
+			result = append(result, kanji)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,67 @@
+package dictionary
+
+import (
+	"strings"
+	"testing"
+)
+
+const testKradfile = `# KRADFILE
+亜 : ｜ 一 口
+唖 : ｜ 一 口
+休 : 化 木
+呆 : 口 木
+`
+
+const testRadkfile = `# RADKFILE
+$ 一 1
+亜唖
+$ ｜ 1
+亜唖
+$ 化 2 js01
+休
+$ 口 3
+亜唖呆
+$ 木 4
+休呆
+`
+
+func loadTestRadicals(t *testing.T) *Radicals {
+	r, err := LoadRadicals(strings.NewReader(testKradfile), strings.NewReader(testRadkfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestLoadRadicals(t *testing.T) {
+	r := loadTestRadicals(t)
+
+	if components := strings.Join(r.Components["亜"], " "); components != "｜ 一 口" {
+		t.Errorf("expected ｜ 一 口, got %s", components)
+	}
+	if r.Strokes["木"] != 4 {
+		t.Errorf("expected 木 to have 4 strokes, got %d", r.Strokes["木"])
+	}
+	if kanji := strings.Join(r.Kanji["口"], ""); kanji != "亜唖呆" {
+		t.Errorf("expected 亜唖呆, got %s", kanji)
+	}
+}
+
+func TestKanjiWith(t *testing.T) {
+	r := loadTestRadicals(t)
+
+	if result := strings.Join(r.KanjiWith([]string{"口", "木"}), ""); result != "呆" {
+		t.Errorf("expected 呆, got %s", result)
+	}
+	// the usual form of a component is accepted too
+	if result := strings.Join(r.KanjiWith([]string{"亻"}), ""); result != "休" {
+		t.Errorf("expected 休, got %s", result)
+	}
+}
+
+func TestLoadRadicalsEUCJP(t *testing.T) {
+	_, err := LoadRadicals(strings.NewReader("\xb0\xa1 : \xb0\xec\n"), strings.NewReader(""))
+	if err == nil {
+		t.Error("expected an error for EUC-JP input")
+	}
+}
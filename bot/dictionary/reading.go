@@ -0,0 +1,38 @@
+package dictionary
+
+import (
+	"sort"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/helpers"
+)
+
+// Readings lists the readings of the entries for a key, most likely first
+func (d *Dictionary) Readings(key string) []string {
+	return ReadingsOf(d.Lookup(key))
+}
+
+// ReadingsOf lists the readings of entries, most likely first. Entries are
+// ordered by priority, and the readings of each entry by the priority of
+// the reading itself
+func ReadingsOf(entries []*jmdict.Entry) []string {
+	var readings []string
+	for _, entry := range SortByPriority(entries) {
+		elements := make([]int, len(entry.ReadingElements))
+		for i := range elements {
+			elements[i] = i
+		}
+		sort.SliceStable(elements, func(i, j int) bool {
+			return priorityScore(entry.ReadingElements[elements[i]].Priorities) >
+				priorityScore(entry.ReadingElements[elements[j]].Priorities)
+		})
+		for _, i := range elements {
+			reading := entry.ReadingElements[i].Phrase
+			if !helpers.StringSliceContains(readings, reading) {
+				readings = append(readings, reading)
+			}
+		}
+	}
+	return readings
+}
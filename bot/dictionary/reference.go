@@ -0,0 +1,74 @@
+package dictionary
+
+import (
+	"strconv"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+// referenceSeparator separates the parts of a JMdict cross-reference
+const referenceSeparator = "・"
+
+// Reference is a parsed JMdict cross-reference or antonym,
+// written as word・reading・sense where the reading and sense are optional
+type Reference struct {
+	Word    string
+	Reading string
+	// Sense is the 1-based sense number referred to, 0 if not given
+	Sense int
+}
+
+// ParseReference parses the text of an xref or ant element
+func ParseReference(text string) Reference {
+	parts := strings.Split(text, referenceSeparator)
+	ref := Reference{Word: parts[0]}
+	for _, part := range parts[1:] {
+		if sense, err := strconv.Atoi(part); err == nil {
+			ref.Sense = sense
+		} else {
+			ref.Reading = part
+		}
+	}
+	return ref
+}
+
+// String formats the reference as it appears in JMdict
+func (r Reference) String() string {
+	parts := []string{r.Word}
+	if r.Reading != "" {
+		parts = append(parts, r.Reading)
+	}
+	if r.Sense > 0 {
+		parts = append(parts, strconv.Itoa(r.Sense))
+	}
+	return strings.Join(parts, referenceSeparator)
+}
+
+// Resolve finds the entries a reference points to
+func (d *Dictionary) Resolve(ref Reference) []*jmdict.Entry {
+	return resolve(d, ref)
+}
+
+func resolve(source Source, ref Reference) []*jmdict.Entry {
+	var result []*jmdict.Entry
+	for _, entry := range source.Lookup(ref.Word) {
+		if ref.Reading != "" && !hasReading(entry, ref.Reading) {
+			continue
+		}
+		if ref.Sense > len(entry.Senses) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func hasReading(entry *jmdict.Entry, reading string) bool {
+	for _, r := range entry.ReadingElements {
+		if r.Phrase == reading {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package dictionary
+
+import "testing"
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected Reference
+	}{
+		{"日本", Reference{Word: "日本"}},
+		{"日本・にほん", Reference{Word: "日本", Reading: "にほん"}},
+		{"日本・2", Reference{Word: "日本", Sense: 2}},
+		{"日本・にっぽん・1", Reference{Word: "日本", Reading: "にっぽん", Sense: 1}},
+	}
+	for _, c := range cases {
+		if result := ParseReference(c.text); result != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.text, c.expected, result)
+		}
+		if result := c.expected.String(); result != c.text {
+			t.Errorf("expected %s, got %s", c.text, result)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package dictionary
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+const (
+	// readingPatternPrefix restricts a search pattern to readings
+	readingPatternPrefix = "[reading]"
+	// maxSearchScan is the most keys a single search will test
+	maxSearchScan = 200000
+	// maxSearchTime is the longest a single search can run for
+	maxSearchTime = 250 * time.Millisecond
+)
+
+// searchIndex holds the keys of a Dictionary sorted forwards and backwards,
+// so patterns with a literal prefix or suffix only scan a range of keys
+type searchIndex struct {
+	forward []string
+	// backward holds every key reversed
+	backward []string
+	readings map[string]bool
+}
+
+// SearchResult is a page of keys matching a search pattern
+type SearchResult struct {
+	Keys []string
+	// More is set if there are more matches after this page
+	More bool
+	// Truncated is set if the search stopped early due to its limits,
+	// in which case there may be matches which weren't found
+	Truncated bool
+}
+
+func (d *Dictionary) createSearchIndex() {
+	idx := &searchIndex{readings: make(map[string]bool)}
+	for key := range d.Index {
+		if key == "" {
+			continue
+		}
+		idx.forward = append(idx.forward, key)
+		idx.backward = append(idx.backward, reverse(key))
+	}
+	sort.Strings(idx.forward)
+	sort.Strings(idx.backward)
+
+	for _, entry := range d.entries {
+		for _, reading := range entry.ReadingElements {
+			idx.readings[reading.Phrase] = true
+		}
+	}
+	d.search = idx
+}
+
+// Search finds keys matching a wildcard pattern, where * matches any
+// number of characters and ? matches exactly one. A pattern beginning with
+// [reading] only matches readings. Results are returned a page at a time
+func (d *Dictionary) Search(pattern string, offset, limit int) SearchResult {
+	var result SearchResult
+
+	pattern, readingOnly := ParsePattern(pattern)
+	if pattern == "" {
+		return result
+	}
+	patternRunes := []rune(pattern)
+
+	var (
+		prefix, suffix = literalEnds(pattern)
+		keys           []string
+		backward       bool
+	)
+	switch {
+	case !strings.ContainsAny(pattern, "*?"):
+		if _, ok := d.Index[pattern]; ok {
+			keys = []string{pattern}
+		}
+	default:
+		// scan whichever of the prefix and suffix ranges is smaller
+		keys = prefixRange(d.search.forward, prefix)
+		if suffix != "" {
+			if suffixKeys := prefixRange(d.search.backward, reverse(suffix)); len(suffixKeys) < len(keys) {
+				keys, backward = suffixKeys, true
+			}
+		}
+	}
+
+	deadline := time.Now().Add(maxSearchTime)
+	matched := 0
+	for i, key := range keys {
+		if i >= maxSearchScan || (i%1024 == 0 && time.Now().After(deadline)) {
+			result.Truncated = true
+			break
+		}
+		if backward {
+			key = reverse(key)
+		}
+		if readingOnly && !d.search.readings[key] {
+			continue
+		}
+		if !matchPattern(patternRunes, []rune(key)) {
+			continue
+		}
+
+		matched++
+		if matched <= offset {
+			continue
+		}
+		if len(result.Keys) == limit {
+			result.More = true
+			break
+		}
+		result.Keys = append(result.Keys, key)
+	}
+	return result
+}
+
+// ParsePattern removes the [reading] prefix from a search pattern,
+// returning the pattern and whether it only matches readings
+func ParsePattern(pattern string) (string, bool) {
+	readingOnly := strings.HasPrefix(pattern, readingPatternPrefix)
+	pattern = strings.TrimPrefix(pattern, readingPatternPrefix)
+	// accept full width wildcards typed with a Japanese IME
+	return kana.FoldWidth(pattern), readingOnly
+}
+
+// literalEnds returns the text before the first and after the last wildcard
+func literalEnds(pattern string) (string, string) {
+	first := strings.IndexAny(pattern, "*?")
+	if first == -1 {
+		return pattern, pattern
+	}
+	last := strings.LastIndexAny(pattern, "*?")
+	return pattern[:first], pattern[last+1:]
+}
+
+// prefixRange returns the sorted keys beginning with prefix
+func prefixRange(sorted []string, prefix string) []string {
+	start := sort.SearchStrings(sorted, prefix)
+	end := start + sort.Search(len(sorted)-start, func(i int) bool {
+		return !strings.HasPrefix(sorted[start+i], prefix)
+	})
+	return sorted[start:end]
+}
+
+// matchPattern matches runes against a pattern of * and ? wildcards
+func matchPattern(pattern, runes []rune) bool {
+	var (
+		p, r         int
+		starP, starR = -1, 0
+	)
+	for r < len(runes) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == runes[r]):
+			p++
+			r++
+		case p < len(pattern) && pattern[p] == '*':
+			starP, starR = p, r
+			p++
+		case starP != -1:
+			// let the last * absorb another rune and retry
+			starR++
+			p, r = starP+1, starR
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
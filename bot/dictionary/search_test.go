@@ -0,0 +1,47 @@
+package dictionary
+
+import "testing"
+
+func TestSearch(t *testing.T) {
+	d := loadTestDictionary(t)
+
+	cases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"日本*", []string{"日本", "日本語"}},
+		{"*語", []string{"語", "日本語"}},
+		{"?語", nil},
+		{"??語", []string{"日本語"}},
+		{"*ん*", []string{"にほん", "にほんご", "べんきょう"}},
+		{"[reading]に*", []string{"にほん", "にほんご"}},
+		{"[reading]日*", nil},
+		{"日本語", []string{"日本語"}},
+	}
+	for _, c := range cases {
+		result := d.Search(c.pattern, 0, 10)
+		if len(result.Keys) != len(c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.pattern, c.expected, result.Keys)
+			continue
+		}
+		for i, key := range result.Keys {
+			if key != c.expected[i] {
+				t.Errorf("%s: expected %v, got %v", c.pattern, c.expected, result.Keys)
+				break
+			}
+		}
+	}
+}
+
+func TestSearchPages(t *testing.T) {
+	d := loadTestDictionary(t)
+
+	first := d.Search("*", 0, 5)
+	if len(first.Keys) != 5 || !first.More {
+		t.Fatalf("expected a full first page, got %+v", first)
+	}
+	second := d.Search("*", 5, 5)
+	if len(second.Keys) == 0 || second.Keys[0] == first.Keys[0] {
+		t.Errorf("expected the next page, got %+v", second)
+	}
+}
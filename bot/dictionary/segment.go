@@ -0,0 +1,169 @@
+package dictionary
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+const (
+	// unknownCost is the lattice cost of a character with no dictionary match.
+	// It is higher than the cost of a word so that known words are always preferred
+	unknownCost = 2
+	// maxInflectedLength is the longest inflected word, in runes, that will be deinflected
+	maxInflectedLength = 12
+)
+
+// Token is a single segment of an analysed phrase
+type Token struct {
+	// Surface is the text as it appears in the phrase
+	Surface string
+	// Key is the Index key for the token, blank if the token is unknown
+	Key string
+	// Reasons lists the inflections applied to Key to produce Surface
+	Reasons []string
+}
+
+// Known checks if the token has a dictionary entry
+func (t Token) Known() bool {
+	return t.Key != ""
+}
+
+// Matcher finds the words at the start of some text in a dictionary,
+// which is all phrases need to be segmented
+type Matcher interface {
+	// MatchPrefixes finds the keys text starts with, shortest first
+	MatchPrefixes(text string) []string
+	// Deinflect finds the dictionary forms of an inflected word
+	Deinflect(word string) []Deinflection
+}
+
+// Segment splits a phrase into tokens in reading order.
+// Each whitespace separated part of the phrase is segmented with a lattice
+// which minimises the number of tokens, preferring the longest match when
+// two segmentations cost the same. Runs of unknown characters are merged
+// into a single token. Parts written in romaji are converted to hiragana,
+// and words are matched on their normalised forms
+func (d *Dictionary) Segment(phrase string) []Token {
+	return SegmentWith(d, phrase)
+}
+
+// SegmentText is Segment for text which is shown as it was written,
+// leaving romaji as it is so the surface of every token is in the text
+func (d *Dictionary) SegmentText(text string) []Token {
+	return SegmentTextWith(d, text)
+}
+
+// SegmentWith is Segment for any dictionary
+func SegmentWith(m Matcher, phrase string) []Token {
+	return segment(m, phrase, true)
+}
+
+// SegmentTextWith is SegmentText for any dictionary
+func SegmentTextWith(m Matcher, text string) []Token {
+	return segment(m, text, false)
+}
+
+func segment(m Matcher, phrase string, romaji bool) []Token {
+	var tokens []Token
+	for _, part := range strings.Fields(kana.FoldWidth(phrase)) {
+		if romaji {
+			part = kana.FromInput(part)
+		}
+		runes := []rune(part)
+		tokens = append(tokens, segmentPart(m, runes, kana.FoldRunes(runes))...)
+	}
+	return tokens
+}
+
+// segmentPart segments runes, matching words against their folded form
+func segmentPart(m Matcher, runes, folded []rune) []Token {
+	n := len(runes)
+	// cost[i] is the cheapest cost of segmenting runes[i:], next[i] is the
+	// first token on that path and is blank if the character is unknown
+	cost := make([]int, n+1)
+	next := make([]Token, n+1)
+	for i := n - 1; i >= 0; i-- {
+		cost[i] = cost[i+1] + unknownCost
+		next[i] = Token{}
+		for _, match := range matchesAt(m, runes[i:], folded[i:]) {
+			length := len([]rune(match.Surface))
+			// matches are ascending in length, so <= prefers the longest
+			if c := cost[i+length] + 1; c <= cost[i] {
+				cost[i] = c
+				next[i] = match
+			}
+		}
+	}
+
+	var (
+		tokens  []Token
+		unknown strings.Builder
+	)
+	for i := 0; i < n; {
+		if !next[i].Known() {
+			unknown.WriteRune(runes[i])
+			i++
+			continue
+		}
+		if unknown.Len() > 0 {
+			tokens = append(tokens, Token{Surface: unknown.String()})
+			unknown.Reset()
+		}
+		tokens = append(tokens, next[i])
+		i += len([]rune(next[i].Surface))
+	}
+	if unknown.Len() > 0 {
+		tokens = append(tokens, Token{Surface: unknown.String()})
+	}
+	return tokens
+}
+
+// matchesAt finds every word at the start of runes, shortest first.
+// Exact matches are preferred over deinflected ones of the same length
+func matchesAt(m Matcher, runes, folded []rune) []Token {
+	var (
+		matches []Token
+		exact   = m.MatchPrefixes(string(runes))
+	)
+	exactMatch := func(length int) bool {
+		if len(exact) == 0 || utf8.RuneCountInString(exact[0]) != length {
+			return false
+		}
+		matches = append(matches, Token{Surface: string(runes[:length]), Key: exact[0]})
+		exact = exact[1:]
+		return true
+	}
+	for length := 1; length <= len(runes) && length <= maxInflectedLength; length++ {
+		if exactMatch(length) {
+			continue
+		}
+		// inflections always end in hiragana
+		if !kana.IsHiragana(folded[length-1]) {
+			continue
+		}
+		if deinflections := m.Deinflect(string(folded[:length])); len(deinflections) > 0 {
+			matches = append(matches, Token{
+				Surface: string(runes[:length]),
+				Key:     deinflections[0].Key,
+				Reasons: deinflections[0].Reasons,
+			})
+		}
+	}
+	// exact matches longer than any inflection
+	for len(exact) > 0 {
+		exactMatch(utf8.RuneCountInString(exact[0]))
+	}
+	return matches
+}
+
+// exactMatch creates a token for a word found in the trie,
+// keyed by the word as written if it is in Index
+func (d *Dictionary) exactMatch(runes, folded []rune) Token {
+	word := string(runes)
+	if _, ok := d.Index[word]; ok {
+		return Token{Surface: word, Key: word}
+	}
+	return Token{Surface: word, Key: string(folded)}
+}
@@ -0,0 +1,117 @@
+package dictionary
+
+import (
+	"strings"
+	"testing"
+)
+
+const testJMdict = `<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>日本</keb></k_ele><r_ele><reb>にほん</reb></r_ele><sense><gloss>Japan</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>日本語</keb></k_ele><r_ele><reb>にほんご</reb></r_ele><sense><gloss>Japanese (language)</gloss></sense></entry>
+<entry><ent_seq>3</ent_seq><k_ele><keb>語</keb></k_ele><r_ele><reb>ご</reb></r_ele><sense><gloss>word</gloss></sense></entry>
+<entry><ent_seq>4</ent_seq><r_ele><reb>を</reb></r_ele><sense><gloss>indicates direct object of action</gloss></sense></entry>
+<entry><ent_seq>5</ent_seq><k_ele><keb>勉強</keb></k_ele><r_ele><reb>べんきょう</reb></r_ele><sense><gloss>study</gloss></sense></entry>
+<entry><ent_seq>6</ent_seq><r_ele><reb>する</reb></r_ele><sense><gloss>to do</gloss></sense></entry>
+</JMdict>`
+
+func loadTestDictionary(t *testing.T) *Dictionary {
+	d, err := Load(strings.NewReader(testJMdict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestSegmentLongestMatch(t *testing.T) {
+	d := loadTestDictionary(t)
+
+	result := d.Segment("日本語を勉強する")
+	expected := []string{"日本語", "を", "勉強", "する"}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i, token := range result {
+		if token.Surface != expected[i] || !token.Known() {
+			t.Errorf("token %d: expected %s, got %+v", i, expected[i], token)
+		}
+	}
+}
+
+func TestSegmentUnknown(t *testing.T) {
+	d := loadTestDictionary(t)
+
+	result := d.Segment("ABC日本 XYZ")
+	if len(result) != 3 {
+		t.Fatalf("expected 3 tokens, got %v", result)
+	}
+	if result[0].Surface != "ABC" || result[0].Known() {
+		t.Errorf("expected unknown ABC, got %+v", result[0])
+	}
+	if result[1].Key != "日本" {
+		t.Errorf("expected 日本, got %+v", result[1])
+	}
+	if result[2].Surface != "XYZ" || result[2].Known() {
+		t.Errorf("expected unknown XYZ, got %+v", result[2])
+	}
+}
+
+const testInflectedJMdict = `<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>食べる</keb></k_ele><r_ele><reb>たべる</reb></r_ele><sense><pos>&v1;</pos><gloss>to eat</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>高い</keb></k_ele><r_ele><reb>たかい</reb></r_ele><sense><pos>&adj-i;</pos><gloss>high</gloss></sense></entry>
+<entry><ent_seq>3</ent_seq><k_ele><keb>勉強</keb></k_ele><r_ele><reb>べんきょう</reb></r_ele><sense><pos>&n;</pos><pos>&vs;</pos><gloss>study</gloss></sense></entry>
+<entry><ent_seq>4</ent_seq><k_ele><keb>察する</keb></k_ele><r_ele><reb>さっする</reb></r_ele><sense><pos>&vs-s;</pos><gloss>to guess</gloss></sense></entry>
+</JMdict>`
+
+func TestSegmentInflected(t *testing.T) {
+	d, err := Load(strings.NewReader(testInflectedJMdict))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		phrase  string
+		key     string
+		reasons string
+	}{
+		{"食べました", "食べる", "polite past"},
+		{"食べませんでした", "食べる", "polite past negative"},
+		{"食べられなかった", "食べる", "potential or passive negative past"},
+		{"高くなかった", "高い", "negative past"},
+		{"勉強しました", "勉強", "polite past"},
+		{"察さなかった", "察する", "negative past"},
+		{"察せます", "察する", "potential polite"},
+		{"察しません", "察する", "polite negative"},
+	}
+	for _, c := range cases {
+		result := d.Segment(c.phrase)
+		if len(result) != 1 {
+			t.Errorf("%s: expected 1 token, got %v", c.phrase, result)
+			continue
+		}
+		if result[0].Key != c.key || strings.Join(result[0].Reasons, " ") != c.reasons {
+			t.Errorf("%s: expected %s (%s), got %+v", c.phrase, c.key, c.reasons, result[0])
+		}
+	}
+}
+
+func TestSegmentNormalised(t *testing.T) {
+	d, err := Load(strings.NewReader(testInflectedJMdict))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, phrase := range []string{"taberu", "タベル", "ﾀﾍﾞﾙ", "tabemashita"} {
+		result := d.Segment(phrase)
+		if len(result) != 1 || !result[0].Known() {
+			t.Errorf("%s: expected one known token, got %v", phrase, result)
+			continue
+		}
+		if entries := d.Lookup(result[0].Key); len(entries) != 1 || Headword(entries[0]) != "食べる" {
+			t.Errorf("%s: expected 食べる, got %v", phrase, entries)
+		}
+	}
+
+	if entries := d.Lookup("takai"); len(entries) != 1 {
+		t.Errorf("expected romaji lookup to find 高い, got %v", entries)
+	}
+}
@@ -0,0 +1,206 @@
+package dictionary
+
+import (
+	"sort"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// SourceInfo describes a dictionary source
+type SourceInfo struct {
+	// Name identifies the source, e.g. JMdict
+	Name string
+	// Description is shown above the source's definitions, if it is set
+	Description string
+	// Mark is shown next to the source's words in analyse results
+	Mark string
+	// Fallback sources are only used for keys no earlier source has
+	Fallback bool
+}
+
+// Source is a dictionary words can be looked up in
+type Source interface {
+	Info() SourceInfo
+	// Lookup finds the entries for a key
+	Lookup(key string) []*jmdict.Entry
+	// LookupID finds the entries with an ID
+	LookupID(id string) []*jmdict.Entry
+	// SearchGloss finds entries by the words of their English glosses
+	SearchGloss(query string, limit int) []GlossMatch
+	// MatchPrefixes finds the keys text starts with, shortest first
+	MatchPrefixes(text string) []string
+}
+
+// Info returns the metadata of the dictionary
+func (d *Dictionary) Info() SourceInfo {
+	return d.info
+}
+
+// WithInfo sets the metadata of the dictionary, returning it
+func (d *Dictionary) WithInfo(info SourceInfo) *Dictionary {
+	d.info = info
+	return d
+}
+
+// LookupID finds the entries with an ID
+func (d *Dictionary) LookupID(id string) []*jmdict.Entry {
+	return d.IndexByID[id]
+}
+
+// Contains checks if a key is in the index exactly as written
+func (d *Dictionary) Contains(key string) bool {
+	_, ok := d.Index[key]
+	return ok
+}
+
+// MatchPrefixes finds the keys text starts with, shortest first
+func (d *Dictionary) MatchPrefixes(text string) []string {
+	var (
+		keys   []string
+		runes  = []rune(text)
+		folded = kana.FoldRunes(runes)
+	)
+	for _, length := range d.trie.PrefixLengths(folded) {
+		keys = append(keys, d.exactMatch(runes[:length], folded[:length]).Key)
+	}
+	return keys
+}
+
+// Result is an entry tagged with the source it came from
+type Result struct {
+	Entry  *jmdict.Entry
+	Source SourceInfo
+}
+
+// WordMatch is a word found in some text by a source
+type WordMatch struct {
+	Key    string
+	Source SourceInfo
+}
+
+// Sources queries several sources together, in the order they were added
+type Sources struct {
+	sources []Source
+}
+
+// NewSources creates a Sources from a list of sources
+func NewSources(sources ...Source) *Sources {
+	return &Sources{sources: sources}
+}
+
+// Add adds a source after the existing ones
+func (s *Sources) Add(source Source) {
+	s.sources = append(s.sources, source)
+}
+
+// All returns every source in order
+func (s *Sources) All() []Source {
+	return s.sources
+}
+
+// Named finds a source by name, returning nil if there isn't one
+func (s *Sources) Named(name string) Source {
+	for _, source := range s.sources {
+		if source.Info().Name == name {
+			return source
+		}
+	}
+	return nil
+}
+
+// Lookup finds the entries for a key in every source, grouped by source
+// with the most common entries of each first
+func (s *Sources) Lookup(key string) []Result {
+	return s.collect(func(source Source) []*jmdict.Entry {
+		return source.Lookup(key)
+	})
+}
+
+// LookupID finds the entries with an ID in every source
+func (s *Sources) LookupID(id string) []Result {
+	return s.collect(func(source Source) []*jmdict.Entry {
+		return source.LookupID(id)
+	})
+}
+
+// Resolve finds the entries a reference points to in every source
+func (s *Sources) Resolve(ref Reference) []Result {
+	return s.collect(func(source Source) []*jmdict.Entry {
+		return resolve(source, ref)
+	})
+}
+
+func (s *Sources) collect(find func(source Source) []*jmdict.Entry) []Result {
+	var results []Result
+	for _, source := range s.sources {
+		info := source.Info()
+		if info.Fallback && len(results) > 0 {
+			continue
+		}
+		for _, entry := range SortByPriority(find(source)) {
+			results = append(results, Result{Entry: entry, Source: info})
+		}
+	}
+	return results
+}
+
+// SearchGloss searches the glosses of every source, ranking the matches of
+// all sources together. Fallback sources are only searched if no earlier
+// source has a match
+func (s *Sources) SearchGloss(query string, limit int) []GlossMatch {
+	var matches []GlossMatch
+	for _, source := range s.sources {
+		info := source.Info()
+		if info.Fallback && len(matches) > 0 {
+			continue
+		}
+		for _, match := range source.SearchGloss(query, limit) {
+			match.Source = info
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return betterGlossMatch(matches[i], matches[j])
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// FindWords scans text for the longest word at each position in any source,
+// skipping characters no word starts with
+func (s *Sources) FindWords(text string) []WordMatch {
+	var (
+		matches []WordMatch
+		runes   = []rune(text)
+	)
+	for i := 0; i < len(runes); {
+		var (
+			best   WordMatch
+			length = 0
+		)
+		for _, source := range s.sources {
+			keys := source.MatchPrefixes(string(runes[i:]))
+			if len(keys) == 0 {
+				continue
+			}
+			// keys are shortest first
+			key := keys[len(keys)-1]
+			if l := len([]rune(key)); l > length {
+				best = WordMatch{Key: key, Source: source.Info()}
+				length = l
+			}
+		}
+		if length == 0 {
+			i++
+			continue
+		}
+		matches = append(matches, best)
+		i += length
+	}
+	return matches
+}
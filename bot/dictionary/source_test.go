@@ -0,0 +1,94 @@
+package dictionary
+
+import (
+	"strings"
+	"testing"
+)
+
+const testGlossary = `# guild glossary
+鯖	さば	Discord server; guild
+ぬるぽ		null pointer exception
+`
+
+func loadTestSources(t *testing.T) *Sources {
+	g, err := LoadGlossary(strings.NewReader(testGlossary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	glossary := New(g).WithInfo(SourceInfo{Name: "Glossary"})
+
+	names, err := Load(strings.NewReader(`<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>田中</keb></k_ele><r_ele><reb>たなか</reb></r_ele><sense><gloss>Tanaka</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>日本</keb></k_ele><r_ele><reb>にほん</reb></r_ele><sense><gloss>Nihon</gloss></sense></entry>
+</JMdict>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names.WithInfo(SourceInfo{Name: "JMnedict", Fallback: true})
+
+	return NewSources(loadTestDictionary(t), glossary, names)
+}
+
+func TestLoadGlossary(t *testing.T) {
+	g, err := LoadGlossary(strings.NewReader(testGlossary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(g.Entries))
+	}
+	if glosses := g.Entries[0].Senses[0].GlossaryItems; len(glosses) != 2 || glosses[1].Definition != "guild" {
+		t.Errorf("expected two meanings, got %+v", glosses)
+	}
+	if len(g.Entries[1].KanjiElements) != 0 || g.Entries[1].ReadingElements[0].Phrase != "ぬるぽ" {
+		t.Errorf("expected a kana only entry, got %+v", g.Entries[1])
+	}
+
+	if _, err := LoadGlossary(strings.NewReader("鯖 さば\n")); err == nil {
+		t.Error("expected an error for a line without tabs")
+	}
+}
+
+func TestSourcesLookup(t *testing.T) {
+	s := loadTestSources(t)
+
+	results := s.Lookup("さば")
+	if len(results) != 1 || results[0].Source.Name != "Glossary" {
+		t.Errorf("expected a glossary result, got %+v", results)
+	}
+	// fallback sources are skipped when an earlier source has the key
+	if results := s.Lookup("日本"); len(results) != 1 || results[0].Source.Name != "JMdict" {
+		t.Errorf("expected only JMdict for 日本, got %+v", results)
+	}
+	if results := s.Lookup("田中"); len(results) != 1 || results[0].Source.Name != "JMnedict" {
+		t.Errorf("expected JMnedict for 田中, got %+v", results)
+	}
+}
+
+func TestSourcesSearchGloss(t *testing.T) {
+	s := loadTestSources(t)
+
+	matches := s.SearchGloss("guild", 10)
+	if len(matches) != 1 || matches[0].Source.Name != "Glossary" {
+		t.Errorf("expected a glossary match, got %+v", matches)
+	}
+	// a limit of 0 returns every match
+	if matches := s.SearchGloss("guild", 0); len(matches) != 1 {
+		t.Errorf("expected 1 match without a limit, got %+v", matches)
+	}
+}
+
+func TestSourcesFindWords(t *testing.T) {
+	s := loadTestSources(t)
+
+	matches := s.FindWords("田中は鯖")
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 words, got %+v", matches)
+	}
+	if matches[0].Key != "田中" || matches[0].Source.Name != "JMnedict" {
+		t.Errorf("expected 田中 from JMnedict, got %+v", matches[0])
+	}
+	if matches[1].Key != "鯖" || matches[1].Source.Name != "Glossary" {
+		t.Errorf("expected 鯖 from the glossary, got %+v", matches[1])
+	}
+}
@@ -0,0 +1,203 @@
+package dictionary
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/datafile"
+)
+
+// Progress is called with the percentage of a dictionary file read so far,
+// each time it goes up
+type Progress func(percent int)
+
+// xmlEntry decodes an entry of a JMdict document
+type xmlEntry struct {
+	EntryID         string                  `xml:"ent_seq"`
+	KanjiElements   []jmdict.KanjiElement   `xml:"k_ele"`
+	ReadingElements []jmdict.ReadingElement `xml:"r_ele"`
+	Senses          []xmlSense              `xml:"sense"`
+}
+
+// xmlSense decodes a sense, with the languages of its lsource elements.
+// jmdict.SourceLanguage looks for an attribute called "xml:lang", which
+// never matches since encoding/xml reads it as lang in the xml namespace
+type xmlSense struct {
+	jmdict.Sense
+	SourceLanguages []xmlSourceLanguage `xml:"lsource"`
+}
+
+type xmlSourceLanguage struct {
+	Language string `xml:"lang,attr"`
+	Type     string `xml:"ls_type,attr"`
+	Wasei    string `xml:"ls_wasei,attr"`
+}
+
+// tailSize is how much of the end of a file lineReader keeps
+const tailSize = 64 * 1024
+
+// lineReader counts the lines read through it, keeping the end of what
+// it has read so it can find the line of a recent offset
+type lineReader struct {
+	r       io.Reader
+	counter *datafile.CountingReader
+	lines   int
+	tail    []byte
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: r, counter: datafile.NewCountingReader(r)}
+}
+
+func (c *lineReader) Read(p []byte) (int, error) {
+	n, err := c.counter.Read(p)
+	c.lines += bytes.Count(p[:n], []byte{'\n'})
+	c.tail = append(c.tail, p[:n]...)
+	if len(c.tail) > 2*tailSize {
+		c.tail = append(c.tail[:0], c.tail[len(c.tail)-tailSize:]...)
+	}
+	return n, err
+}
+
+// line finds the line of an offset, or 0 if it is too far back
+func (c *lineReader) line(offset int64) int {
+	ahead := c.counter.Position() - offset
+	if ahead < 0 || ahead > int64(len(c.tail)) {
+		return 0
+	}
+	return c.lines - bytes.Count(c.tail[int64(len(c.tail))-ahead:], []byte{'\n'}) + 1
+}
+
+// position returns how far through its file the reader is. Data files
+// report how much of the file on disk has been read, which differs if
+// they're compressed
+func (c *lineReader) position() int64 {
+	if p, ok := c.r.(interface{ Position() int64 }); ok {
+		return p.Position()
+	}
+	return c.counter.Position()
+}
+
+// lineError adds the line a decoding error happened on
+func lineError(err error, dec *xml.Decoder, c *lineReader) error {
+	if _, ok := err.(*xml.SyntaxError); ok {
+		// syntax errors already have their line
+		return err
+	}
+	if line := c.line(dec.InputOffset()); line > 0 {
+		return fmt.Errorf("line %d: %s", line, err.Error())
+	}
+	return err
+}
+
+// DecodeEntries decodes a JMdict document one entry at a time, calling
+// f with each entry as it is read, so the whole document is never held
+// in memory. Repeated strings such as tags are shared between entries.
+// If size is above 0, progress is called as the reader is read through
+func DecodeEntries(r io.Reader, size int64, progress Progress, f func(entry *jmdict.Entry)) error {
+	counter := newLineReader(r)
+	dec := xml.NewDecoder(counter)
+	dec.Entity = jmdict.Entities
+
+	shared := make(interner)
+	percent := 0
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return lineError(err, dec, counter)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "entry" {
+			continue
+		}
+		var e xmlEntry
+		if err := dec.DecodeElement(&e, &start); err != nil {
+			return lineError(err, dec, counter)
+		}
+		entry := e.toEntry()
+		shared.entry(entry)
+		f(entry)
+
+		if progress != nil && size > 0 {
+			if p := int(counter.position() * 100 / size); p > percent {
+				percent = p
+				progress(percent)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *xmlEntry) toEntry() *jmdict.Entry {
+	entry := &jmdict.Entry{
+		EntryID:         e.EntryID,
+		KanjiElements:   e.KanjiElements,
+		ReadingElements: e.ReadingElements,
+		Senses:          make([]jmdict.Sense, len(e.Senses)),
+	}
+	for i, s := range e.Senses {
+		sense := s.Sense
+		sense.SourceLanguages = nil
+		for _, source := range s.SourceLanguages {
+			sense.SourceLanguages = append(sense.SourceLanguages, jmdict.SourceLanguage(source))
+		}
+		entry.Senses[i] = sense
+	}
+	return entry
+}
+
+// interner shares equal strings, since every tag in JMdict is expanded
+// into its own copy of the tag's description
+type interner map[string]string
+
+func (in interner) string(s string) string {
+	if shared, ok := in[s]; ok {
+		return shared
+	}
+	in[s] = s
+	return s
+}
+
+func (in interner) strings(s []string) {
+	for i := range s {
+		s[i] = in.string(s[i])
+	}
+}
+
+// entry interns the tags and languages of an entry
+func (in interner) entry(entry *jmdict.Entry) {
+	for i := range entry.KanjiElements {
+		in.strings(entry.KanjiElements[i].Info)
+		in.strings(entry.KanjiElements[i].Priorities)
+	}
+	for i := range entry.ReadingElements {
+		in.strings(entry.ReadingElements[i].Info)
+		in.strings(entry.ReadingElements[i].Priorities)
+	}
+	for i := range entry.Senses {
+		sense := &entry.Senses[i]
+		in.strings(sense.POS)
+		in.strings(sense.Fields)
+		in.strings(sense.Misc)
+		in.strings(sense.Dialects)
+		for j := range sense.SourceLanguages {
+			source := &sense.SourceLanguages[j]
+			source.Language = in.string(source.Language)
+			source.Type = in.string(source.Type)
+			source.Wasei = in.string(source.Wasei)
+		}
+		for j := range sense.GlossaryItems {
+			gloss := &sense.GlossaryItems[j]
+			gloss.Language = in.string(gloss.Language)
+			gloss.Gender = in.string(gloss.Gender)
+			gloss.Type = in.string(gloss.Type)
+		}
+	}
+}
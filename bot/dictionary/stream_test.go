@@ -0,0 +1,68 @@
+package dictionary
+
+import (
+	"strings"
+	"testing"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+const testLoanwordJMdict = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMdict [
+<!ENTITY n "noun (common) (futsuumeishi)">
+]>
+<JMdict>
+<entry><ent_seq>1</ent_seq><r_ele><reb>アルバイト</reb></r_ele><sense><pos>&n;</pos><lsource xml:lang="ger">Arbeit</lsource><gloss>part-time job</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><r_ele><reb>パン</reb></r_ele><sense><pos>&n;</pos><lsource xml:lang="por">pão</lsource><gloss>bread</gloss><gloss xml:lang="dut">brood</gloss></sense></entry>
+</JMdict>`
+
+func TestDecodeEntries(t *testing.T) {
+	var (
+		entries  []*jmdict.Entry
+		progress []int
+	)
+	err := DecodeEntries(
+		strings.NewReader(testLoanwordJMdict),
+		int64(len(testLoanwordJMdict)),
+		func(percent int) { progress = append(progress, percent) },
+		func(entry *jmdict.Entry) { entries = append(entries, entry) },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	sources := entries[0].Senses[0].SourceLanguages
+	if len(sources) != 1 || sources[0].Language != "ger" {
+		t.Errorf("expected German, got %+v", sources)
+	}
+	if pos := entries[1].Senses[0].POS; len(pos) != 1 || pos[0] != "noun (common) (futsuumeishi)" {
+		t.Errorf("expected a common noun, got %v", pos)
+	}
+	if glosses := entries[1].Senses[0].GlossaryItems; len(glosses) != 2 || glosses[1].Language != "dut" {
+		t.Errorf("expected a Dutch gloss, got %+v", glosses)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != 100 {
+		t.Errorf("expected progress up to 100%%, got %v", progress)
+	}
+}
+
+func TestLoadIndexesEntries(t *testing.T) {
+	d := loadTestDictionary(t)
+	if len(d.Entries()) != 6 {
+		t.Errorf("expected 6 entries, got %d", len(d.Entries()))
+	}
+	if entries := d.IndexByID["2"]; len(entries) != 1 || entries[0] != d.Lookup("日本語")[0] {
+		t.Errorf("expected the same entry by ID and key, got %v", entries)
+	}
+}
+
+func TestDecodeEntriesErrorLine(t *testing.T) {
+	document := "<JMdict>\n<entry><ent_seq>1</ent_seq></entry>\n<entry><ent_seq>2</ent_seq><sense></entry>\n</JMdict>"
+	err := DecodeEntries(strings.NewReader(document), 0, nil, func(*jmdict.Entry) {})
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected an error on line 3, got %v", err)
+	}
+}
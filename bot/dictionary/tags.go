@@ -0,0 +1,62 @@
+package dictionary
+
+import jmdict "github.com/hakasec/jmdict-go"
+
+// entityCodes maps the descriptions in jmdict.Entities back to their codes
+var entityCodes map[string]string
+
+func init() {
+	entityCodes = make(map[string]string, len(jmdict.Entities))
+	for code, description := range jmdict.Entities {
+		entityCodes[description] = code
+	}
+}
+
+// TagCode returns the JMdict entity code for a tag.
+// Tags are expanded to their descriptions when the XML is decoded,
+// so this reverses that where possible
+func TagCode(tag string) string {
+	if code, ok := entityCodes[tag]; ok {
+		return code
+	}
+	return tag
+}
+
+// TagDescription returns the description of a JMdict tag
+func TagDescription(tag string) string {
+	if description, ok := jmdict.Entities[tag]; ok {
+		return description
+	}
+	return tag
+}
+
+// languageNames maps the ISO 639-2 codes used in lsource elements to names
+var languageNames = map[string]string{
+	"ain": "Ainu",
+	"ara": "Arabic",
+	"chi": "Chinese",
+	"dut": "Dutch",
+	"eng": "English",
+	"fre": "French",
+	"ger": "German",
+	"gre": "Greek",
+	"ita": "Italian",
+	"kor": "Korean",
+	"lat": "Latin",
+	"por": "Portuguese",
+	"rus": "Russian",
+	"san": "Sanskrit",
+	"spa": "Spanish",
+}
+
+// LanguageName returns the name of a loanword source language.
+// A blank code means English, as in JMdict
+func LanguageName(code string) string {
+	if code == "" {
+		code = "eng"
+	}
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,90 @@
+package dictionary
+
+import "sort"
+
+// Trie is a prefix tree over the keys of a Dictionary.
+// Children are kept in sorted slices rather than maps to keep the memory
+// footprint reasonable for the few hundred thousand keys in JMdict
+type Trie struct {
+	root trieNode
+	size int
+}
+
+type trieNode struct {
+	r        rune
+	terminal bool
+	children []*trieNode
+}
+
+// NewTrie creates an empty Trie
+func NewTrie() *Trie {
+	return &Trie{}
+}
+
+// Insert adds a key to the trie
+func (t *Trie) Insert(key string) {
+	if key == "" {
+		return
+	}
+
+	node := &t.root
+	for _, r := range key {
+		node = node.child(r, true)
+	}
+	if !node.terminal {
+		node.terminal = true
+		t.size++
+	}
+}
+
+// Contains checks if the exact key has been inserted
+func (t *Trie) Contains(key string) bool {
+	node := &t.root
+	for _, r := range key {
+		if node = node.child(r, false); node == nil {
+			return false
+		}
+	}
+	return node.terminal
+}
+
+// Len returns the number of keys in the trie
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// PrefixLengths returns the length, in runes, of every key which is a prefix
+// of the given runes, shortest first
+func (t *Trie) PrefixLengths(runes []rune) []int {
+	var lengths []int
+
+	node := &t.root
+	for i, r := range runes {
+		if node = node.child(r, false); node == nil {
+			break
+		}
+		if node.terminal {
+			lengths = append(lengths, i+1)
+		}
+	}
+	return lengths
+}
+
+// child finds the child node for a rune, optionally creating it
+func (n *trieNode) child(r rune, create bool) *trieNode {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].r >= r
+	})
+	if i < len(n.children) && n.children[i].r == r {
+		return n.children[i]
+	}
+	if !create {
+		return nil
+	}
+
+	c := &trieNode{r: r}
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+	return c
+}
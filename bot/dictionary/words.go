@@ -0,0 +1,42 @@
+package dictionary
+
+import (
+	"math/rand"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+// Words is the main dictionary, which phrases are split into words with.
+// Dictionary holds it in memory and store.Store reads it from the database
+type Words interface {
+	Source
+	// Deinflect finds the dictionary forms of an inflected word
+	Deinflect(word string) []Deinflection
+	// Contains checks if a key is in the dictionary exactly as written
+	Contains(key string) bool
+	// Segment splits a phrase into tokens, see Dictionary.Segment
+	Segment(phrase string) []Token
+	// SegmentText is Segment for text which is shown as it was written
+	SegmentText(text string) []Token
+	// Readings lists the readings of the entries for a key, most likely first
+	Readings(key string) []string
+	// KeyPriority returns the highest priority of the entries for a key
+	KeyPriority(key string) int
+	// IsCommonKey checks if any entry for a key is a common word
+	IsCommonKey(key string) bool
+	// Search finds keys matching a wildcard pattern, see Dictionary.Search
+	Search(pattern string, offset, limit int) SearchResult
+	// CommonWordsContaining finds the common words which contain a character,
+	// most common and then shortest first
+	CommonWordsContaining(character string, limit int) []string
+	// RandomEntry picks an entry at random, returning nil if there are none
+	RandomEntry() *jmdict.Entry
+}
+
+// RandomEntry picks an entry at random, returning nil if there are none
+func (d *Dictionary) RandomEntry() *jmdict.Entry {
+	if len(d.entries) == 0 {
+		return nil
+	}
+	return d.entries[rand.Intn(len(d.entries))]
+}
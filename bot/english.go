@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/helpers"
+)
+
+// maxEnglishResults is the number of results shown by jpn!en
+const maxEnglishResults = 10
+
+func (b *JapanBot) english(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered any English words!")
+		return
+	}
+
+	query := strings.Join(args[1:], " ")
+	matches := b.sources().SearchGloss(query, maxEnglishResults)
+	if len(matches) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No definitions found :(")
+		return
+	}
+
+	var (
+		words   []string
+		message strings.Builder
+	)
+	message.WriteString(fmt.Sprintf("```\nResults for \"%s\":\n", query))
+	width := helpers.GetNumDigits(len(matches))
+	for i, match := range matches {
+		headword := dictionary.Headword(match.Entry)
+		words = append(words, headword)
+		if match.Source.Mark != "" {
+			headword = match.Source.Mark + headword
+		} else if dictionary.IsCommon(match.Entry) {
+			headword = commonMark + headword
+		}
+
+		reading := ""
+		if len(match.Entry.KanjiElements) > 0 && len(match.Entry.ReadingElements) > 0 {
+			reading = fmt.Sprintf(" (%s)", match.Entry.ReadingElements[0].Phrase)
+		}
+		message.WriteString(
+			fmt.Sprintf(
+				"%d: %s%s%s - %s\n",
+				i+1,
+				strings.Repeat(" ", width-helpers.GetNumDigits(i+1)),
+				headword,
+				reading,
+				match.Gloss,
+			),
+		)
+	}
+	message.WriteString(
+		fmt.Sprintf("\nUse jpn!analyse [1-%d] for the full definition\n", len(matches)),
+	)
+	message.WriteString("```")
+
+	b.setAnalyseRequest(m.ChannelID, words)
+	s.ChannelMessageSend(m.ChannelID, message.String())
+}
@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/tatoeba"
+)
+
+const (
+	// maxExamples is the number of sentences shown by jpn!examples
+	maxExamples = 5
+	// maxExampleLength is the longest sentence shown, in characters
+	maxExampleLength = 40
+)
+
+func (b *JapanBot) examples(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if b.configuration.TatoebaPairsFile == "" || b.configuration.TatoebaIndicesFile == "" {
+		s.ChannelMessageSend(m.ChannelID, "Example sentences aren't available, sorry!")
+		return
+	}
+	corpus := b.exampleCorpus()
+	if corpus == nil {
+		s.ChannelMessageSend(m.ChannelID, "Example sentences are still loading, try again soon!")
+		return
+	}
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a word!")
+		return
+	}
+
+	word := strings.Join(args[1:], "")
+	var examples []tatoeba.Example
+	for _, headword := range b.exampleHeadwords(word) {
+		if examples = corpus.Lookup(headword, maxExamples, maxExampleLength); len(examples) > 0 {
+			break
+		}
+	}
+	if len(examples) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No examples found :(")
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("```\nExamples of %s:\n", word))
+	for i, example := range examples {
+		message.WriteString(
+			fmt.Sprintf("\n%d. %s\n   %s\n", i+1, example.Japanese, example.English),
+		)
+	}
+	message.WriteString("```")
+	s.ChannelMessageSend(m.ChannelID, message.String())
+}
+
+// exampleHeadwords finds the headwords the examples of a word could be
+// indexed by, which are written with kanji where the word has them
+func (b *JapanBot) exampleHeadwords(word string) []string {
+	headwords := []string{word}
+	key := word
+	dicts := b.dictionaries()
+	if tokens := dicts.dictionary.Segment(word); len(tokens) == 1 && tokens[0].Known() {
+		key = tokens[0].Key
+	}
+	for _, result := range dicts.sources.Lookup(key) {
+		headwords = append(headwords, entryHeadwords(result.Entry)...)
+	}
+	return headwords
+}
+
+// entryHeadwords lists the kanji and then the readings of an entry
+func entryHeadwords(entry *jmdict.Entry) []string {
+	var headwords []string
+	for _, kanji := range entry.KanjiElements {
+		headwords = append(headwords, kanji.Phrase)
+	}
+	for _, reading := range entry.ReadingElements {
+		headwords = append(headwords, reading.Phrase)
+	}
+	return headwords
+}
+
+// buildExample shows an example sentence for an entry, if there is one
+func (b *JapanBot) buildExample(entry *jmdict.Entry) string {
+	corpus := b.exampleCorpus()
+	if corpus == nil {
+		return ""
+	}
+	for _, headword := range entryHeadwords(entry) {
+		if examples := corpus.Lookup(headword, 1, maxExampleLength); len(examples) > 0 {
+			return fmt.Sprintf("\ne.g. %s\n     %s\n", examples[0].Japanese, examples[0].English)
+		}
+	}
+	return ""
+}
+
+// exampleCorpus returns the example sentences, or nil until they're loaded
+func (b *JapanBot) exampleCorpus() *tatoeba.Corpus {
+	b.examplesLock.RLock()
+	defer b.examplesLock.RUnlock()
+	return b.examplesCorpus
+}
+
+// loadExamples loads the example sentences in the background,
+// since building their index takes a while the first time
+func (b *JapanBot) loadExamples() {
+	corpus, err := tatoeba.Open(
+		b.configuration.TatoebaPairsFile,
+		b.configuration.TatoebaIndicesFile,
+	)
+	if err != nil {
+		fmt.Printf("Error loading example sentences: %s\n", err.Error())
+		return
+	}
+
+	b.examplesLock.Lock()
+	b.examplesCorpus = corpus
+	b.examplesLock.Unlock()
+	fmt.Printf("Loaded %d example sentences\n", len(corpus.Examples))
+}
@@ -0,0 +1,25 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/furigana"
+	"github.com/hakasec/japanbot-go/bot/helpers"
+)
+
+func (b *JapanBot) furigana(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a sentence!")
+		return
+	}
+
+	parts := furigana.Annotate(b.dictionary(), strings.Join(args[1:], " "))
+	if helpers.StringSliceContains(commandKeywords(args[0]), "ruby") {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("```html\n%s\n```", furigana.Ruby(parts)))
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, furigana.Bracket(parts))
+}
@@ -0,0 +1,205 @@
+// Package furigana annotates Japanese text with the readings of its kanji
+package furigana
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// Part is a piece of annotated text
+type Part struct {
+	Text string
+	// Reading is the reading of Text in hiragana, blank if it needs none
+	Reading string
+}
+
+// Annotate segments a sentence with the dictionary and attaches the most
+// likely reading to each word containing kanji. Readings are aligned so
+// that okurigana is left unannotated, e.g. 食[た]べる
+func Annotate(d dictionary.Words, sentence string) []Part {
+	var parts []Part
+	for i, field := range strings.Fields(kana.FoldWidth(sentence)) {
+		if i > 0 {
+			parts = appendPart(parts, Part{Text: " "})
+		}
+		for _, token := range d.SegmentText(field) {
+			for _, part := range annotateToken(d, token) {
+				parts = appendPart(parts, part)
+			}
+		}
+	}
+	return parts
+}
+
+// appendPart appends a part, merging it with the last part if neither has a reading
+func appendPart(parts []Part, part Part) []Part {
+	if last := len(parts) - 1; last >= 0 && part.Reading == "" && parts[last].Reading == "" {
+		parts[last].Text += part.Text
+		return parts
+	}
+	return append(parts, part)
+}
+
+func annotateToken(d dictionary.Words, token dictionary.Token) []Part {
+	if !token.Known() || !hasKanji(token.Surface) {
+		return []Part{{Text: token.Surface}}
+	}
+	for _, reading := range d.Readings(token.Key) {
+		surfaceReading, ok := inflectReading(token.Key, reading, token.Surface)
+		if !ok {
+			continue
+		}
+		if parts, ok := Align(token.Surface, surfaceReading); ok {
+			return parts
+		}
+	}
+	return []Part{{Text: token.Surface}}
+}
+
+// inflectReading gives the reading of surface, an inflected form of key,
+// by swapping the okurigana of the key's reading for that of surface
+func inflectReading(key, reading, surface string) (string, bool) {
+	var (
+		keyRunes     = []rune(key)
+		surfaceRunes = []rune(surface)
+		n            = 0
+	)
+	for n < len(keyRunes) && n < len(surfaceRunes) && keyRunes[n] == surfaceRunes[n] {
+		n++
+	}
+	keyRest := kana.ToHiragana(string(keyRunes[n:]))
+	surfaceRest := kana.ToHiragana(string(surfaceRunes[n:]))
+	// inflection only changes okurigana
+	if hasKanji(keyRest) || hasKanji(surfaceRest) {
+		return "", false
+	}
+
+	reading = kana.ToHiragana(reading)
+	if !strings.HasSuffix(reading, keyRest) {
+		return "", false
+	}
+	return strings.TrimSuffix(reading, keyRest) + surfaceRest, true
+}
+
+// Align splits text into runs of kanji and kana, giving each run of kanji
+// its part of reading. It reports false if the kana in text don't match reading
+func Align(text, reading string) ([]Part, bool) {
+	var (
+		runs    = splitRuns(text)
+		pattern strings.Builder
+	)
+	pattern.WriteString("^")
+	for _, run := range runs {
+		if isKanaRun(run) {
+			pattern.WriteString(regexp.QuoteMeta(kana.ToHiragana(run)))
+		} else {
+			pattern.WriteString("(.+?)")
+		}
+	}
+	pattern.WriteString("$")
+
+	match := regexp.MustCompile(pattern.String()).FindStringSubmatch(kana.ToHiragana(reading))
+	if match == nil {
+		return nil, false
+	}
+
+	var (
+		parts []Part
+		group = 1
+	)
+	for _, run := range runs {
+		if isKanaRun(run) {
+			parts = append(parts, Part{Text: run})
+		} else {
+			parts = append(parts, Part{Text: run, Reading: match[group]})
+			group++
+		}
+	}
+	return parts, true
+}
+
+// splitRuns splits text into alternating runs of kana and other characters
+func splitRuns(text string) []string {
+	var (
+		runs    []string
+		current []rune
+	)
+	for _, r := range text {
+		if len(current) > 0 && isKana(current[0]) != isKana(r) {
+			runs = append(runs, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		runs = append(runs, string(current))
+	}
+	return runs
+}
+
+func isKana(r rune) bool {
+	return kana.IsHiragana(r) || kana.IsKatakana(r) || r == 'ー'
+}
+
+func isKanaRun(run string) bool {
+	for _, r := range run {
+		return isKana(r)
+	}
+	return false
+}
+
+func hasKanji(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bracket formats parts with each reading in brackets after its kanji,
+// e.g. 日本語[にほんご]を勉強[べんきょう]する
+func Bracket(parts []Part) string {
+	var builder strings.Builder
+	for _, part := range parts {
+		builder.WriteString(part.Text)
+		if part.Reading != "" {
+			builder.WriteString("[" + part.Reading + "]")
+		}
+	}
+	return builder.String()
+}
+
+// Ruby formats parts as HTML, with each reading in a ruby annotation
+func Ruby(parts []Part) string {
+	var builder strings.Builder
+	for _, part := range parts {
+		text := html.EscapeString(part.Text)
+		if part.Reading != "" {
+			builder.WriteString(
+				"<ruby>" + text + "<rt>" + html.EscapeString(part.Reading) + "</rt></ruby>",
+			)
+		} else {
+			builder.WriteString(text)
+		}
+	}
+	return builder.String()
+}
+
+// Reading joins the readings of parts, giving the whole text in kana
+func Reading(parts []Part) string {
+	var builder strings.Builder
+	for _, part := range parts {
+		if part.Reading != "" {
+			builder.WriteString(part.Reading)
+		} else {
+			builder.WriteString(part.Text)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,62 @@
+package furigana
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+const testJMdict = `<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>日本語</keb></k_ele><r_ele><reb>にほんご</reb></r_ele><sense><gloss>Japanese (language)</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><r_ele><reb>を</reb></r_ele><sense><gloss>indicates direct object of action</gloss></sense></entry>
+<entry><ent_seq>3</ent_seq><k_ele><keb>勉強</keb></k_ele><r_ele><reb>べんきょう</reb></r_ele><sense><pos>&n;</pos><pos>&vs;</pos><gloss>study</gloss></sense></entry>
+<entry><ent_seq>4</ent_seq><r_ele><reb>する</reb></r_ele><sense><gloss>to do</gloss></sense></entry>
+<entry><ent_seq>5</ent_seq><k_ele><keb>食べる</keb></k_ele><r_ele><reb>たべる</reb></r_ele><sense><pos>&v1;</pos><gloss>to eat</gloss></sense></entry>
+<entry><ent_seq>6</ent_seq><k_ele><keb>日</keb></k_ele><r_ele><reb>ひ</reb></r_ele><sense><gloss>day</gloss></sense></entry>
+<entry><ent_seq>7</ent_seq><k_ele><keb>日</keb></k_ele><r_ele><reb>にち</reb><re_pri>ichi1</re_pri></r_ele><sense><gloss>Sunday</gloss></sense></entry>
+</JMdict>`
+
+func TestAnnotate(t *testing.T) {
+	d, err := dictionary.Load(strings.NewReader(testJMdict))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"日本語を勉強する": "日本語[にほんご]を勉強[べんきょう]する",
+		"食べました":    "食[た]べました",
+		"勉強しました":   "勉強[べんきょう]しました",
+		"日":        "日[にち]",
+		"ABC 日本語":  "ABC 日本語[にほんご]",
+		// romaji is left as it was written
+		"benkyou 日本語": "benkyou 日本語[にほんご]",
+	}
+	for in, expected := range cases {
+		if result := Bracket(Annotate(d, in)); result != expected {
+			t.Errorf("%s: expected %s, got %s", in, expected, result)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	parts, ok := Align("取り扱い", "とりあつかい")
+	if !ok {
+		t.Fatal("expected 取り扱い to align")
+	}
+	if result := Bracket(parts); result != "取[と]り扱[あつか]い" {
+		t.Errorf("expected 取[と]り扱[あつか]い, got %s", result)
+	}
+
+	if _, ok := Align("食べる", "のむ"); ok {
+		t.Error("expected 食べる not to align with のむ")
+	}
+}
+
+func TestRuby(t *testing.T) {
+	parts := []Part{{Text: "日本語", Reading: "にほんご"}, {Text: "<b>"}}
+	expected := "<ruby>日本語<rt>にほんご</rt></ruby>&lt;b&gt;"
+	if result := Ruby(parts); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
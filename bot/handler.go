@@ -5,13 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/hakasec/japanbot-go/bot/database/models"
+	"github.com/hakasec/japanbot-go/bot/dictionary"
 	"github.com/hakasec/japanbot-go/bot/helpers"
+	"github.com/hakasec/japanbot-go/bot/kana"
+	"github.com/hakasec/japanbot-go/bot/pitch"
 	jmdict "github.com/hakasec/jmdict-go"
 )
 
@@ -24,15 +28,38 @@ type HandlerFunc func(args []string, s *discordgo.Session, m *discordgo.Message)
 // HandlerMap is a map of commands to HandlerFuncs
 type HandlerMap map[string]HandlerFunc
 
+// commandKeywords returns the keywords following the command name,
+// e.g. jpn!analyse!ngrams gives [ngrams]
+func commandKeywords(command string) []string {
+	keywords := strings.Split(command, "!")
+	if len(keywords) < 3 {
+		return nil
+	}
+	return keywords[2:]
+}
+
 func (b *JapanBot) createHandlerMap() HandlerMap {
 	return HandlerMap{
-		"analyze": b.analyse,
-		"analyse": b.analyse,
-		"answer":  b.answer,
-		"help":    b.help,
-		"hentai":  b.hentai,
-		"enable":  b.enableFeature,
-		"disable": b.disableFeature,
+		"analyze":     b.analyse,
+		"analyse":     b.analyse,
+		"answer":      b.answer,
+		"bycomponent": b.bycomponent,
+		"conjugate":   b.conjugate,
+		"en":          b.english,
+		"examples":    b.examples,
+		"furigana":    b.furigana,
+		"help":        b.help,
+		"kanji":       b.kanji,
+		"name":        b.name,
+		"radicals":    b.radicals,
+		"reload":      b.reload,
+		"romaji":      b.romaji,
+		"search":      b.search,
+		"see":         b.see,
+		"strokes":     b.strokes,
+		"hentai":      b.hentai,
+		"enable":      b.enableFeature,
+		"disable":     b.disableFeature,
 	}
 }
 
@@ -44,36 +71,50 @@ func (b *JapanBot) analyse(args []string, s *discordgo.Session, m *discordgo.Mes
 
 	var response string
 	phrase := strings.Join(args[1:], " ")
+	// the whole command uses the dictionaries loaded when it started
+	dicts := b.dictionaries()
 
 	if helpers.IsDigits(phrase) {
 		selection, err := strconv.ParseInt(phrase, 0, 0)
 		if err != nil {
 			panic(err)
 		}
-		response = b.buildSelectionResponse(int(selection), m)
+		response = b.buildSelectionResponse(dicts.sources, int(selection), m)
+	} else if helpers.StringSliceContains(commandKeywords(args[0]), "ngrams") {
+		allGrams := ngramCandidates(dicts, phrase)
+		b.setAnalyseRequest(m.ChannelID, allGrams)
+		response = b.buildAnalyseResponse("", keyLabels(dicts.dictionary, allGrams), nil)
 	} else {
-		var allGrams []string
-		// generate a list of ngrams of size 1 through len(phrase)
-		for ngramSize := 1; ngramSize <= len(phrase); ngramSize++ {
-			tmpNgram := helpers.CreateNgrams(phrase, ngramSize)
-			for _, gram := range tmpNgram {
-				// remove spaces
-				gram = strings.Replace(gram, " ", "", -1)
-				if gram == "" {
-					continue
-				}
-				// check if already in list
-				if !helpers.StringSliceContains(allGrams, gram) {
-					// check for definition
-					if _, ok := b.dictionary.Index[gram]; ok {
-						// add to list
-						allGrams = append(allGrams, gram)
+		var (
+			words    []string
+			labels   []string
+			segments []string
+			sources  []dictionary.SourceInfo
+		)
+		for _, token := range dicts.dictionary.Segment(phrase) {
+			segments = append(segments, token.Surface)
+			if token.Known() && !helpers.StringSliceContains(words, token.Key) {
+				words = append(words, token.Key)
+				labels = append(labels, tokenLabel(dicts.dictionary, token))
+			} else if !token.Known() {
+				// unknown words may be in another source, such as names
+				for _, match := range dicts.sources.FindWords(token.Surface) {
+					if !helpers.StringSliceContains(words, match.Key) {
+						words = append(words, match.Key)
+						labels = append(labels, sourceLabel(dicts.dictionary, match.Key, match.Source))
+						sources = appendSource(sources, match.Source)
 					}
 				}
 			}
 		}
-		b.analyseRequests[m.ChannelID] = allGrams
-		response = b.buildAnalyseResponse(allGrams)
+		if len(words) == 0 {
+			// nothing segmented, fall back to every ngram with a definition
+			words = ngramCandidates(dicts, phrase)
+			labels = keyLabels(dicts.dictionary, words)
+			segments = nil
+		}
+		b.setAnalyseRequest(m.ChannelID, words)
+		response = b.buildAnalyseResponse(strings.Join(segments, " | "), labels, sources)
 	}
 
 	responses := strings.Split(response, "--")
@@ -88,77 +129,334 @@ func (b *JapanBot) analyse(args []string, s *discordgo.Session, m *discordgo.Mes
 	}
 }
 
-func (b *JapanBot) buildSelectionResponse(selection int, m *discordgo.Message) string {
-	r, ok := b.analyseRequests[m.ChannelID]
+// commonMark is shown next to common words
+const commonMark = "★ "
+
+// tokenLabel describes a token in an analyse response,
+// showing the dictionary form and inflections of inflected words
+func tokenLabel(d dictionary.Words, token dictionary.Token) string {
+	label := keyLabel(d, token.Key)
+	if len(token.Reasons) == 0 {
+		return label
+	}
+	return fmt.Sprintf(
+		"%s → %s (%s)",
+		token.Surface,
+		label,
+		strings.Join(token.Reasons, " "),
+	)
+}
+
+// keyLabel marks a key if it is a common word
+func keyLabel(d dictionary.Words, key string) string {
+	if d.IsCommonKey(key) {
+		return commonMark + key
+	}
+	return key
+}
+
+// sourceLabel marks a key with the source it was found in,
+// or as a common word if it is from the main dictionary
+func sourceLabel(d dictionary.Words, key string, source dictionary.SourceInfo) string {
+	if source.Mark == "" {
+		return keyLabel(d, key)
+	}
+	return source.Mark + key
+}
+
+// appendSource adds a source to a list if it has a mark and isn't in the list
+func appendSource(sources []dictionary.SourceInfo, source dictionary.SourceInfo) []dictionary.SourceInfo {
+	if source.Mark == "" {
+		return sources
+	}
+	for _, s := range sources {
+		if s.Name == source.Name {
+			return sources
+		}
+	}
+	return append(sources, source)
+}
+
+func keyLabels(d dictionary.Words, keys []string) []string {
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = keyLabel(d, key)
+	}
+	return labels
+}
+
+// ngramCandidates generates a list of every ngram in a phrase which has a
+// definition, with the most common words first
+func ngramCandidates(dicts *dictionaries, phrase string) []string {
+	var allGrams []string
+	// generate a list of ngrams of size 1 through len(phrase)
+	for ngramSize := 1; ngramSize <= len(phrase); ngramSize++ {
+		tmpNgram := helpers.CreateNgrams(phrase, ngramSize)
+		for _, gram := range tmpNgram {
+			// remove spaces
+			gram = strings.Replace(gram, " ", "", -1)
+			if gram == "" {
+				continue
+			}
+			// check if already in list
+			if !helpers.StringSliceContains(allGrams, gram) {
+				// check for definition
+				if len(dicts.sources.Lookup(gram)) > 0 {
+					// add to list
+					allGrams = append(allGrams, gram)
+				}
+			}
+		}
+	}
+	priorities := make(map[string]int, len(allGrams))
+	for _, gram := range allGrams {
+		priorities[gram] = dicts.dictionary.KeyPriority(gram)
+	}
+	sort.SliceStable(allGrams, func(i, j int) bool {
+		return priorities[allGrams[i]] > priorities[allGrams[j]]
+	})
+	return allGrams
+}
+
+// analyseRequest returns the words last listed in a channel
+func (b *JapanBot) analyseRequest(channelID string) ([]string, bool) {
+	b.requestsLock.RLock()
+	defer b.requestsLock.RUnlock()
+	words, ok := b.analyseRequests[channelID]
+	return words, ok
+}
+
+// setAnalyseRequest sets the words listed in a channel, to be selected by number
+func (b *JapanBot) setAnalyseRequest(channelID string, words []string) {
+	b.requestsLock.Lock()
+	b.analyseRequests[channelID] = words
+	b.requestsLock.Unlock()
+}
+
+func (b *JapanBot) buildSelectionResponse(sources *dictionary.Sources, selection int, m *discordgo.Message) string {
+	r, ok := b.analyseRequest(m.ChannelID)
 	if !ok {
 		return "You haven't specified anything to be defined!"
 	}
 
 	if selection-1 < len(r) {
-		entries, ok := b.dictionary.Index[r[selection-1]]
-		if ok {
-			var message strings.Builder
-			message.WriteString("```")
-			for _, e := range entries {
-				tmp := fmt.Sprintf("%s\n\n", b.buildDefinition(e, "eng"))
-				lastSplit := strings.LastIndex(message.String(), "--")
-				if lastSplit == -1 {
-					lastSplit = 0
-				}
-				if (message.Len()-lastSplit)+len(tmp) >= 1996 {
-					message.WriteString("\n```--```\n")
-				}
-				message.WriteString(tmp)
-			}
-			message.WriteString("```")
-			return message.String()
+		results := sources.Lookup(r[selection-1])
+		if len(results) > 0 {
+			return b.buildResultsResponse(results, &definitionOptions{examples: true}, m)
 		}
 		return "No definition for this word!"
 	}
 	return "Definition index is invalid!"
 }
 
-func (b *JapanBot) buildDefinition(entry *jmdict.Entry, langCode string) string {
+// definitionOptions controls how definitions are built
+type definitionOptions struct {
+	langCode string
+	// sense limits the definition to a single sense when above 0
+	sense int
+	// references collects the cross-references shown, which are numbered in order
+	references []dictionary.Reference
+	// romaji is the style readings are romanised in, nil to hide romaji
+	romaji *kana.RomajiOptions
+	// source is the source of the entry being built
+	source dictionary.SourceInfo
+	// examples adds an example sentence to each entry
+	examples bool
+}
+
+// buildResultsResponse builds the definitions of a list of results, in order,
+// storing their cross-references for the author to follow with jpn!see
+func (b *JapanBot) buildResultsResponse(results []dictionary.Result, opts *definitionOptions, m *discordgo.Message) string {
+	if romaji, show := b.romajiOptions(m); show {
+		opts.romaji = &romaji
+	}
+
+	var message strings.Builder
+	message.WriteString("```")
+	for _, result := range results {
+		opts.source = result.Source
+		tmp := fmt.Sprintf("%s\n\n", b.buildDefinition(result.Entry, opts))
+		lastSplit := strings.LastIndex(message.String(), "--")
+		if lastSplit == -1 {
+			lastSplit = 0
+		}
+		if (message.Len()-lastSplit)+len(tmp) >= 1996 {
+			message.WriteString("\n```--```\n")
+		}
+		message.WriteString(tmp)
+	}
+	message.WriteString("```")
+
+	b.setReferences(m.Author.ID, opts.references)
+	return message.String()
+}
+
+func (b *JapanBot) buildDefinition(entry *jmdict.Entry, opts *definitionOptions) string {
+	langCode := opts.langCode
 	if langCode == "" {
 		langCode = "eng"
 	}
 	var message strings.Builder
+	if opts.source.Description != "" {
+		message.WriteString(fmt.Sprintln(opts.source.Mark + opts.source.Description))
+	}
+	if dictionary.IsCommon(entry) {
+		message.WriteString(fmt.Sprintln(commonMark + "common word"))
+	}
 	for _, reading := range entry.KanjiElements {
 		message.WriteString(fmt.Sprintln(reading.Phrase))
 	}
 	for _, reading := range entry.ReadingElements {
+		phrase := reading.Phrase
+		if opts.romaji != nil {
+			phrase = fmt.Sprintf("%s [%s]", phrase, kana.ToRomaji(phrase, *opts.romaji))
+		}
 		if reading.PhraseNoKanji != "" {
 			message.WriteString(
-				fmt.Sprintf("%s (%s)\n", reading.Phrase, reading.PhraseNoKanji),
+				fmt.Sprintf("%s (%s)\n", phrase, reading.PhraseNoKanji),
 			)
 		} else {
-			message.WriteString(fmt.Sprintln(reading.Phrase))
+			message.WriteString(fmt.Sprintln(phrase))
 		}
+		message.WriteString(b.buildAccents(entry, reading.Phrase))
 	}
-	message.WriteString("\n")
-	for _, sense := range entry.Senses {
+	for i, sense := range entry.Senses {
+		if opts.sense > 0 && i+1 != opts.sense {
+			continue
+		}
+
+		var glosses []string
 		for _, gloss := range sense.GlossaryItems {
 			language := gloss.Language
 			if language == "" {
 				language = "eng"
 			}
 			if language == langCode {
-				message.WriteString(
-					fmt.Sprintf("%s\n", gloss.Definition),
-				)
+				glosses = append(glosses, gloss.Definition)
 			}
 		}
+		if len(glosses) == 0 {
+			continue
+		}
+
+		message.WriteString(
+			fmt.Sprintf("\n%d. %s\n", i+1, strings.Join(glosses, "; ")),
+		)
+		message.WriteString(buildSenseDetails(sense, opts))
+	}
+	if opts.examples {
+		message.WriteString(b.buildExample(entry))
+	}
+	return message.String()
+}
+
+// buildAccents draws the pitch accents of a reading of an entry,
+// with a contour above the reading followed by its downstep and pattern
+func (b *JapanBot) buildAccents(entry *jmdict.Entry, reading string) string {
+	if b.accents == nil {
+		return ""
+	}
+
+	var accents []pitch.Accent
+	for _, kanji := range entry.KanjiElements {
+		if accents = b.accents.Lookup(kanji.Phrase, reading); len(accents) > 0 {
+			break
+		}
+	}
+	if len(entry.KanjiElements) == 0 {
+		accents = b.accents.Lookup(reading, reading)
+	}
+
+	var message strings.Builder
+	for _, accent := range accents {
+		note := ""
+		if accent.Note != "" {
+			note = fmt.Sprintf(" (%s)", accent.Note)
+		}
+		message.WriteString(
+			fmt.Sprintf(
+				"   %s\n   %s [%d] %s%s\n",
+				pitch.Contour(reading, accent.Downstep),
+				reading,
+				accent.Downstep,
+				pitch.Pattern(reading, accent.Downstep),
+				note,
+			),
+		)
+	}
+	return message.String()
+}
+
+// buildSenseDetails describes the tags, origin and references of a sense
+func buildSenseDetails(sense jmdict.Sense, opts *definitionOptions) string {
+	var message strings.Builder
+	writeLine := func(label string, values []string) {
+		if len(values) > 0 {
+			message.WriteString(
+				fmt.Sprintf("   %s%s\n", label, strings.Join(values, "; ")),
+			)
+		}
+	}
+
+	writeLine("", describeTags(sense.POS))
+	writeLine("Misc: ", describeTags(sense.Misc))
+	writeLine("Field: ", describeTags(sense.Fields))
+	writeLine("Dialect: ", describeTags(sense.Dialects))
+
+	var origins []string
+	for _, source := range sense.SourceLanguages {
+		origin := dictionary.LanguageName(source.Language)
+		if source.Type == "part" {
+			origin += " (partial)"
+		}
+		if source.Wasei == "y" {
+			origin += " (wasei-eigo)"
+		}
+		origins = append(origins, origin)
 	}
+	writeLine("Origin: ", origins)
+
+	writeLine("See also: ", numberReferences(sense.CrossReferences, opts))
+	writeLine("Antonym: ", numberReferences(sense.Antonyms, opts))
 	return message.String()
 }
 
-func (b *JapanBot) buildAnalyseResponse(ngrams []string) string {
+// numberReferences parses and numbers references, adding them to opts
+func numberReferences(references []string, opts *definitionOptions) []string {
+	var numbered []string
+	for _, text := range references {
+		opts.references = append(opts.references, dictionary.ParseReference(text))
+		numbered = append(
+			numbered,
+			fmt.Sprintf("[%d] %s", len(opts.references), text),
+		)
+	}
+	return numbered
+}
+
+// describeTags expands a list of JMdict tags to their descriptions
+func describeTags(tags []string) []string {
+	var descriptions []string
+	for _, tag := range tags {
+		descriptions = append(
+			descriptions,
+			dictionary.TagDescription(dictionary.TagCode(tag)),
+		)
+	}
+	return descriptions
+}
+
+func (b *JapanBot) buildAnalyseResponse(segmented string, ngrams []string, sources []dictionary.SourceInfo) string {
 	if len(ngrams) == 0 {
 		return "No definitions found :("
 	}
 
 	var message strings.Builder
-	message.WriteString("```\nPick a phrase:\n")
+	message.WriteString("```\n")
+	if segmented != "" {
+		message.WriteString(fmt.Sprintf("%s\n\n", segmented))
+	}
+	message.WriteString("Pick a phrase:\n")
 	width := helpers.GetNumDigits(len(ngrams))
 	for i, gram := range ngrams {
 		tmp := fmt.Sprintf(
@@ -180,6 +478,10 @@ func (b *JapanBot) buildAnalyseResponse(ngrams []string) string {
 	message.WriteString(
 		fmt.Sprintf("\nUse jpn!analyse [1-%d]\n", len(ngrams)),
 	)
+	message.WriteString(fmt.Sprintf("%smarks common words\n", commonMark))
+	for _, source := range sources {
+		message.WriteString(fmt.Sprintf("%smarks words from %s\n", source.Mark, source.Name))
+	}
 	message.WriteString("```")
 	return message.String()
 }
@@ -194,6 +496,42 @@ func (b *JapanBot) help(args []string, s *discordgo.Session, m *discordgo.Messag
 Available Commands:
 
 - analyse/analyze: Analyse a Japanese sentence. 
+  Conjugated verbs and adjectives are shown in their dictionary form.
+  Use jpn!analyse!ngrams to list every phrase found instead.
+
+- conjugate: Show the conjugations of a verb or adjective.
+
+- see: Follow a numbered cross-reference from the last definition you viewed.
+
+- en: Search for Japanese words by their English meaning.
+
+- search: Search for words with wildcards, e.g. 食* or *する.
+  ? matches a single character, and [reading]たべ* only searches readings.
+
+- furigana: Add readings to the kanji in a sentence, e.g. 日本語[にほんご].
+  Use jpn!furigana!ruby for HTML ruby tags instead.
+
+- name: Look up a person's or place's name, e.g. jpn!name たなか.
+
+- kanji: Show the readings, meanings and common words of a kanji.
+
+- radicals: Show the components a kanji is made of.
+
+- bycomponent: Find kanji containing every component given, e.g. 口 木.
+  Add a stroke count or range such as 5-8 to narrow the results.
+
+- strokes: Show the stroke order of a character, one frame per stroke.
+  Use jpn!strokes!diagram for a single numbered diagram instead.
+
+- examples: Show example sentences using a word, with their translations.
+
+- romaji: Romanise a sentence, e.g. jpn!romaji 東京へ行く.
+  Set the style readings are shown in with jpn!romaji!style hepburn, kunrei,
+  nihon or off, optionally followed by macrons or doubled for long vowels.
+  Use jpn!romaji!style!channel to set the default for the channel.
+
+- reload: Reload the dictionary files without restarting, for the bot's
+  owners only, e.g. jpn!reload dictionary.
 
 - help: This help text, silly!  
 `,
@@ -291,15 +629,17 @@ func (b *JapanBot) changeCardMode(channelID string, cardMode int) error {
 }
 
 func (b *JapanBot) generateCard(channelID string) *models.Card {
-	rnd := rand.Intn(len(b.dictionary.Entries))
-	rndEntry := &b.dictionary.Entries[rnd]
+	rndEntry := b.dictionary().RandomEntry()
+	if rndEntry == nil {
+		panic(errors.New("Couldn't generate card"))
+	}
 
 	var phrase string
 	if len(rndEntry.KanjiElements) > 0 {
-		rnd = rand.Intn(len(rndEntry.KanjiElements))
+		rnd := rand.Intn(len(rndEntry.KanjiElements))
 		phrase = rndEntry.KanjiElements[rnd].Phrase
 	} else if len(rndEntry.ReadingElements) > 0 {
-		rnd = rand.Intn(len(rndEntry.ReadingElements))
+		rnd := rand.Intn(len(rndEntry.ReadingElements))
 		phrase = rndEntry.ReadingElements[rnd].Phrase
 	} else {
 		panic(errors.New("Couldn't generate card"))
@@ -330,12 +670,12 @@ func (b *JapanBot) getLatestCard(channelID string) *models.Card {
 
 func (b *JapanBot) answer(args []string, s *discordgo.Session, m *discordgo.Message) {
 	lastCard := b.getLatestCard(m.ChannelID)
-	dictEntries := b.dictionary.IndexByID[lastCard.EntryID]
+	results := b.sources().LookupID(lastCard.EntryID)
 
 	answer := strings.Join(args[1:], " ")
 
-	for _, dictEntry := range dictEntries {
-		for _, sense := range dictEntry.Senses {
+	for _, result := range results {
+		for _, sense := range result.Entry.Senses {
 			for _, item := range sense.GlossaryItems {
 				if strings.ToLower(item.Definition) == strings.ToLower(answer) {
 					s.ChannelMessageSend(m.ChannelID, "Correct!")
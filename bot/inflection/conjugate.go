@@ -0,0 +1,226 @@
+package inflection
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Form is a single conjugated form of a word.
+// Polite is blank if the form has no common polite equivalent
+type Form struct {
+	Name   string
+	Plain  string
+	Polite string
+}
+
+// Conjugate builds a conjugation table for a word of the given
+// JMdict part-of-speech code
+func Conjugate(word, posCode string) ([]Form, error) {
+	switch {
+	case posCode == "v1", posCode == "v1-s":
+		return conjugateIchidan(word, posCode)
+	case strings.HasPrefix(posCode, "v5"):
+		return conjugateGodan(word, posCode)
+	case posCode == "vs", posCode == "vs-i":
+		return conjugateSuru(word)
+	case posCode == "vs-s":
+		return conjugateSuruSpecial(word)
+	case posCode == "vk":
+		return conjugateKuru(word)
+	case posCode == "adj-i", posCode == "adj-ix":
+		return conjugateAdjective(word, posCode)
+	case posCode == "adj-na":
+		return conjugateNaAdjective(word), nil
+	default:
+		return nil, fmt.Errorf("can't conjugate words of type %s", posCode)
+	}
+}
+
+func conjugateGodan(word, posCode string) ([]Form, error) {
+	runes := []rune(word)
+	if len(runes) < 2 {
+		return nil, fmt.Errorf("%s is too short to be a godan verb", word)
+	}
+	stem, u := string(runes[:len(runes)-1]), string(runes[len(runes)-1])
+
+	var row *godanRow
+	for i := range godanRows {
+		if godanRows[i].u == u {
+			row = &godanRows[i]
+		}
+	}
+	if row == nil {
+		return nil, fmt.Errorf("%s doesn't end like a godan verb", word)
+	}
+
+	var (
+		i, te, ta = row.i, row.te, row.ta
+		negative  = stem + row.a + "ない"
+		pastNeg   = stem + row.a + "なかった"
+		imp       = stem + row.e
+	)
+	switch posCode {
+	case "v5k-s":
+		te, ta = "って", "った"
+	case "v5u-s":
+		te, ta = "うて", "うた"
+	case "v5r-i":
+		negative, pastNeg = "ない", "なかった"
+	case "v5aru":
+		i = "い"
+		imp = stem + "い"
+	}
+
+	return []Form{
+		{"Non-past", word, stem + i + "ます"},
+		{"Past", stem + ta, stem + i + "ました"},
+		{"Negative", negative, stem + i + "ません"},
+		{"Past negative", pastNeg, stem + i + "ませんでした"},
+		{"Te-form", stem + te, stem + i + "まして"},
+		{"Potential", stem + row.e + "る", stem + row.e + "ます"},
+		{"Passive", stem + row.a + "れる", stem + row.a + "れます"},
+		{"Causative", stem + row.a + "せる", stem + row.a + "せます"},
+		{"Volitional", stem + row.o + "う", stem + i + "ましょう"},
+		{"Conditional (ba)", stem + row.e + "ば", ""},
+		{"Conditional (tara)", stem + ta + "ら", stem + i + "ましたら"},
+		{"Imperative", imp, stem + i + "なさい"},
+	}, nil
+}
+
+func conjugateIchidan(word, posCode string) ([]Form, error) {
+	if !strings.HasSuffix(word, "る") {
+		return nil, fmt.Errorf("%s doesn't end like an ichidan verb", word)
+	}
+	stem := strings.TrimSuffix(word, "る")
+
+	imp := stem + "ろ"
+	if posCode == "v1-s" {
+		imp = stem
+	}
+
+	return []Form{
+		{"Non-past", word, stem + "ます"},
+		{"Past", stem + "た", stem + "ました"},
+		{"Negative", stem + "ない", stem + "ません"},
+		{"Past negative", stem + "なかった", stem + "ませんでした"},
+		{"Te-form", stem + "て", stem + "まして"},
+		{"Potential", stem + "られる", stem + "られます"},
+		{"Passive", stem + "られる", stem + "られます"},
+		{"Causative", stem + "させる", stem + "させます"},
+		{"Volitional", stem + "よう", stem + "ましょう"},
+		{"Conditional (ba)", stem + "れば", ""},
+		{"Conditional (tara)", stem + "たら", stem + "ましたら"},
+		{"Imperative", imp, stem + "なさい"},
+	}, nil
+}
+
+func conjugateSuru(word string) ([]Form, error) {
+	// nouns which take suru are listed without it
+	stem := strings.TrimSuffix(word, "する")
+
+	return []Form{
+		{"Non-past", stem + "する", stem + "します"},
+		{"Past", stem + "した", stem + "しました"},
+		{"Negative", stem + "しない", stem + "しません"},
+		{"Past negative", stem + "しなかった", stem + "しませんでした"},
+		{"Te-form", stem + "して", stem + "しまして"},
+		{"Potential", stem + "できる", stem + "できます"},
+		{"Passive", stem + "される", stem + "されます"},
+		{"Causative", stem + "させる", stem + "させます"},
+		{"Volitional", stem + "しよう", stem + "しましょう"},
+		{"Conditional (ba)", stem + "すれば", ""},
+		{"Conditional (tara)", stem + "したら", stem + "しましたら"},
+		{"Imperative", stem + "しろ", stem + "しなさい"},
+	}, nil
+}
+
+// conjugateSuruSpecial conjugates verbs such as 愛する, whose single kanji
+// stem makes most of their forms those of the godan verb ending in す
+func conjugateSuruSpecial(word string) ([]Form, error) {
+	if !strings.HasSuffix(word, "する") {
+		return nil, fmt.Errorf("%s doesn't end like a suru verb", word)
+	}
+	stem := strings.TrimSuffix(word, "する")
+
+	forms, err := conjugateGodan(stem+"す", "v5s")
+	if err != nil {
+		return nil, err
+	}
+	for i := range forms {
+		switch forms[i].Name {
+		case "Non-past":
+			forms[i].Plain = word
+		case "Conditional (ba)":
+			forms[i].Plain = stem + "すれば"
+		}
+	}
+	return forms, nil
+}
+
+func conjugateKuru(word string) ([]Form, error) {
+	var prefix, ku, ki, ko string
+	switch {
+	case strings.HasSuffix(word, "来る"):
+		prefix = strings.TrimSuffix(word, "来る")
+		ku, ki, ko = "来", "来", "来"
+	case strings.HasSuffix(word, "くる"):
+		prefix = strings.TrimSuffix(word, "くる")
+		ku, ki, ko = "く", "き", "こ"
+	default:
+		return nil, fmt.Errorf("%s doesn't end like kuru", word)
+	}
+	ku, ki, ko = prefix+ku, prefix+ki, prefix+ko
+
+	return []Form{
+		{"Non-past", ku + "る", ki + "ます"},
+		{"Past", ki + "た", ki + "ました"},
+		{"Negative", ko + "ない", ki + "ません"},
+		{"Past negative", ko + "なかった", ki + "ませんでした"},
+		{"Te-form", ki + "て", ki + "まして"},
+		{"Potential", ko + "られる", ko + "られます"},
+		{"Passive", ko + "られる", ko + "られます"},
+		{"Causative", ko + "させる", ko + "させます"},
+		{"Volitional", ko + "よう", ki + "ましょう"},
+		{"Conditional (ba)", ku + "れば", ""},
+		{"Conditional (tara)", ki + "たら", ki + "ましたら"},
+		{"Imperative", ko + "い", ki + "なさい"},
+	}, nil
+}
+
+func conjugateAdjective(word, posCode string) ([]Form, error) {
+	if !strings.HasSuffix(word, "い") {
+		return nil, fmt.Errorf("%s doesn't end like an i-adjective", word)
+	}
+	stem := strings.TrimSuffix(word, "い")
+	// ii conjugates from yoi
+	if posCode == "adj-ix" && strings.HasSuffix(stem, "い") {
+		stem = strings.TrimSuffix(stem, "い") + "よ"
+	}
+
+	return []Form{
+		{"Non-past", word, word + "です"},
+		{"Past", stem + "かった", stem + "かったです"},
+		{"Negative", stem + "くない", stem + "くありません"},
+		{"Past negative", stem + "くなかった", stem + "くありませんでした"},
+		{"Te-form", stem + "くて", ""},
+		{"Adverbial", stem + "く", ""},
+		{"Volitional", stem + "かろう", word + "でしょう"},
+		{"Conditional (ba)", stem + "ければ", ""},
+		{"Conditional (tara)", stem + "かったら", ""},
+	}, nil
+}
+
+func conjugateNaAdjective(word string) []Form {
+	return []Form{
+		{"Non-past", word + "だ", word + "です"},
+		{"Past", word + "だった", word + "でした"},
+		{"Negative", word + "じゃない", word + "じゃありません"},
+		{"Past negative", word + "じゃなかった", word + "じゃありませんでした"},
+		{"Te-form", word + "で", ""},
+		{"Adverbial", word + "に", ""},
+		{"Attributive", word + "な", ""},
+		{"Volitional", word + "だろう", word + "でしょう"},
+		{"Conditional (ba)", word + "なら", ""},
+		{"Conditional (tara)", word + "だったら", word + "でしたら"},
+	}
+}
@@ -0,0 +1,89 @@
+package inflection
+
+import "testing"
+
+func TestConjugate(t *testing.T) {
+	forms, err := Conjugate("書く", "v5k")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Past":       "書いた",
+		"Negative":   "書かない",
+		"Te-form":    "書いて",
+		"Potential":  "書ける",
+		"Volitional": "書こう",
+		"Imperative": "書け",
+	}
+	for _, form := range forms {
+		if e, ok := expected[form.Name]; ok && e != form.Plain {
+			t.Errorf("%s: expected %s, got %s", form.Name, e, form.Plain)
+		}
+	}
+}
+
+func TestConjugateSuruSpecial(t *testing.T) {
+	forms, err := Conjugate("愛する", "vs-s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Non-past":         "愛する",
+		"Past":             "愛した",
+		"Negative":         "愛さない",
+		"Potential":        "愛せる",
+		"Passive":          "愛される",
+		"Volitional":       "愛そう",
+		"Conditional (ba)": "愛すれば",
+	}
+	for _, form := range forms {
+		if e, ok := expected[form.Name]; ok && e != form.Plain {
+			t.Errorf("%s: expected %s, got %s", form.Name, e, form.Plain)
+		}
+	}
+	if forms[2].Polite != "愛しません" {
+		t.Errorf("expected polite negative 愛しません, got %s", forms[2].Polite)
+	}
+}
+
+func TestConjugateDeinflects(t *testing.T) {
+	words := []struct {
+		word    string
+		posCode string
+	}{
+		{"書く", "v5k"},
+		{"泳ぐ", "v5g"},
+		{"待つ", "v5t"},
+		{"食べる", "v1"},
+		{"する", "vs-i"},
+		{"愛する", "vs-s"},
+		{"来る", "vk"},
+		{"高い", "adj-i"},
+	}
+	for _, w := range words {
+		forms, err := Conjugate(w.word, w.posCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, form := range forms[1:] {
+			// the potential of suru is the separate verb dekiru
+			if w.posCode == "vs-i" && form.Name == "Potential" {
+				continue
+			}
+			if !deinflectsTo(form.Plain, w.word) {
+				t.Errorf("%s (%s) doesn't deinflect to %s", form.Plain, form.Name, w.word)
+			}
+		}
+	}
+}
+
+func deinflectsTo(inflected, word string) bool {
+	for _, c := range Deinflect(inflected) {
+		if c.Word == word {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package inflection
+
+import "strings"
+
+// Type is a bitmask of the word classes a form can belong to
+type Type uint
+
+const (
+	// TypeIchidan is an ichidan (-ru) verb
+	TypeIchidan Type = 1 << iota
+	// TypeGodan is a godan (-u) verb
+	TypeGodan
+	// TypeSuru is the verb suru, or a noun which takes it
+	TypeSuru
+	// TypeKuru is the verb kuru
+	TypeKuru
+	// TypeAdjective is an i-adjective, including the -nai form of verbs
+	TypeAdjective
+	// TypeSuruSpecial is a special suru verb, which also conjugates like
+	// a godan verb ending in su
+	TypeSuruSpecial
+
+	// TypeAny matches every word class
+	TypeAny = TypeIchidan | TypeGodan | TypeSuru | TypeKuru | TypeAdjective | TypeSuruSpecial
+)
+
+// Rule replaces an inflected ending with the ending of a less inflected form
+type Rule struct {
+	Suffix      string
+	Replacement string
+	// From is the word classes the inflected form can belong to
+	From Type
+	// To is the word class of the form produced
+	To     Type
+	Reason string
+}
+
+// Candidate is a possible dictionary form of an inflected word
+type Candidate struct {
+	Word string
+	Type Type
+	// Reasons lists the inflections applied to the dictionary form,
+	// innermost first
+	Reasons []string
+}
+
+type seenKey struct {
+	word string
+	t    Type
+}
+
+// TypeOf converts a JMdict part-of-speech code to a Type,
+// returning 0 if the code is not an inflecting word class
+func TypeOf(posCode string) Type {
+	switch {
+	case posCode == "v1", posCode == "v1-s":
+		return TypeIchidan
+	case strings.HasPrefix(posCode, "v5"):
+		return TypeGodan
+	case posCode == "vs", posCode == "vs-i":
+		return TypeSuru
+	case posCode == "vs-s":
+		return TypeSuru | TypeSuruSpecial
+	case posCode == "vk":
+		return TypeKuru
+	case posCode == "adj-i", posCode == "adj-ix":
+		return TypeAdjective
+	default:
+		return 0
+	}
+}
+
+// Deinflect finds every form a word could have been inflected from.
+// The word itself is not included in the result, and candidates still need
+// checking against the part-of-speech of a dictionary entry
+func Deinflect(word string) []Candidate {
+	var (
+		result []Candidate
+		queue  = []Candidate{{Word: word, Type: TypeAny}}
+		seen   = map[seenKey]bool{}
+	)
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+
+		for _, rule := range Rules {
+			if c.Type&rule.From == 0 || !strings.HasSuffix(c.Word, rule.Suffix) {
+				continue
+			}
+			stem := c.Word[:len(c.Word)-len(rule.Suffix)]
+			if stem == "" && rule.Replacement == "" {
+				continue
+			}
+
+			next := Candidate{
+				Word:    stem + rule.Replacement,
+				Type:    rule.To,
+				Reasons: append([]string{rule.Reason}, c.Reasons...),
+			}
+			key := seenKey{next.Word, next.Type}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			result = append(result, next)
+			queue = append(queue, next)
+		}
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+package inflection
+
+// godanRow holds the kana a godan verb ending changes to in each stem
+type godanRow struct {
+	u, a, i, e, o string
+	te, ta        string
+}
+
+var godanRows = []godanRow{
+	{u: "く", a: "か", i: "き", e: "け", o: "こ", te: "いて", ta: "いた"},
+	{u: "ぐ", a: "が", i: "ぎ", e: "げ", o: "ご", te: "いで", ta: "いだ"},
+	{u: "す", a: "さ", i: "し", e: "せ", o: "そ", te: "して", ta: "した"},
+	{u: "つ", a: "た", i: "ち", e: "て", o: "と", te: "って", ta: "った"},
+	{u: "ぬ", a: "な", i: "に", e: "ね", o: "の", te: "んで", ta: "んだ"},
+	{u: "ぶ", a: "ば", i: "び", e: "べ", o: "ぼ", te: "んで", ta: "んだ"},
+	{u: "む", a: "ま", i: "み", e: "め", o: "も", te: "んで", ta: "んだ"},
+	{u: "る", a: "ら", i: "り", e: "れ", o: "ろ", te: "って", ta: "った"},
+	{u: "う", a: "わ", i: "い", e: "え", o: "お", te: "って", ta: "った"},
+}
+
+// ending is an inflected ending shared by several word classes
+type ending struct {
+	tail   string
+	from   Type
+	reason string
+}
+
+// masuEndings attach to the i-stem of verbs
+var masuEndings = []ending{
+	{"ます", TypeAny, "polite"},
+	{"ました", TypeAny, "polite past"},
+	{"ません", TypeAny, "polite negative"},
+	{"ませんでした", TypeAny, "polite past negative"},
+	{"ましょう", TypeAny, "polite volitional"},
+	{"なさい", TypeAny, "polite imperative"},
+	{"たい", TypeAdjective, "want"},
+}
+
+// Rules is the table of deinflection rules
+var Rules = buildRules()
+
+func buildRules() []Rule {
+	var rules []Rule
+
+	// godan verbs
+	for _, row := range godanRows {
+		rules = append(rules, godanRules(row, row.u, TypeGodan)...)
+	}
+	// iku is irregular in the te and past forms
+	for _, stem := range []string{"い", "行"} {
+		rules = append(rules,
+			Rule{stem + "って", stem + "く", TypeAny, TypeGodan, "te-form"},
+			Rule{stem + "った", stem + "く", TypeAny, TypeGodan, "past"},
+			Rule{stem + "ったら", stem + "く", TypeAny, TypeGodan, "conditional (tara)"},
+			Rule{stem + "っている", stem + "く", TypeIchidan, TypeGodan, "progressive"},
+		)
+	}
+
+	// ichidan verbs
+	for _, e := range masuEndings {
+		rules = append(rules, Rule{e.tail, "る", e.from, TypeIchidan, e.reason})
+	}
+	rules = append(rules,
+		Rule{"ない", "る", TypeAdjective, TypeIchidan, "negative"},
+		Rule{"て", "る", TypeAny, TypeIchidan, "te-form"},
+		Rule{"た", "る", TypeAny, TypeIchidan, "past"},
+		Rule{"たら", "る", TypeAny, TypeIchidan, "conditional (tara)"},
+		Rule{"れば", "る", TypeAny, TypeIchidan, "conditional (ba)"},
+		Rule{"られる", "る", TypeIchidan, TypeIchidan, "potential or passive"},
+		Rule{"させる", "る", TypeIchidan, TypeIchidan, "causative"},
+		Rule{"させられる", "る", TypeIchidan, TypeIchidan, "causative passive"},
+		Rule{"よう", "る", TypeAny, TypeIchidan, "volitional"},
+		Rule{"ろ", "る", TypeAny, TypeIchidan, "imperative"},
+		Rule{"ている", "る", TypeIchidan, TypeIchidan, "progressive"},
+	)
+
+	// suru
+	for _, e := range masuEndings {
+		rules = append(rules, Rule{"し" + e.tail, "する", e.from, TypeSuru, e.reason})
+	}
+	rules = append(rules,
+		Rule{"しない", "する", TypeAdjective, TypeSuru, "negative"},
+		Rule{"して", "する", TypeAny, TypeSuru, "te-form"},
+		Rule{"した", "する", TypeAny, TypeSuru, "past"},
+		Rule{"したら", "する", TypeAny, TypeSuru, "conditional (tara)"},
+		Rule{"すれば", "する", TypeAny, TypeSuru, "conditional (ba)"},
+		Rule{"される", "する", TypeIchidan, TypeSuru, "passive"},
+		Rule{"させる", "する", TypeIchidan, TypeSuru, "causative"},
+		Rule{"させられる", "する", TypeIchidan, TypeSuru, "causative passive"},
+		Rule{"しよう", "する", TypeAny, TypeSuru, "volitional"},
+		Rule{"しろ", "する", TypeAny, TypeSuru, "imperative"},
+		Rule{"せよ", "する", TypeAny, TypeSuru, "imperative"},
+		Rule{"している", "する", TypeIchidan, TypeSuru, "progressive"},
+	)
+	// special suru verbs also take the endings of godan verbs in su
+	for _, row := range godanRows {
+		if row.u == "す" {
+			rules = append(rules, godanRules(row, "する", TypeSuruSpecial)...)
+		}
+	}
+
+	// kuru, written in either kana or kanji
+	for _, stem := range []struct{ i, o, u string }{{"き", "こ", "く"}, {"来", "来", "来"}} {
+		for _, e := range masuEndings {
+			rules = append(rules, Rule{stem.i + e.tail, stem.u + "る", e.from, TypeKuru, e.reason})
+		}
+		rules = append(rules,
+			Rule{stem.o + "ない", stem.u + "る", TypeAdjective, TypeKuru, "negative"},
+			Rule{stem.i + "て", stem.u + "る", TypeAny, TypeKuru, "te-form"},
+			Rule{stem.i + "た", stem.u + "る", TypeAny, TypeKuru, "past"},
+			Rule{stem.i + "たら", stem.u + "る", TypeAny, TypeKuru, "conditional (tara)"},
+			Rule{stem.u + "れば", stem.u + "る", TypeAny, TypeKuru, "conditional (ba)"},
+			Rule{stem.o + "られる", stem.u + "る", TypeIchidan, TypeKuru, "potential or passive"},
+			Rule{stem.o + "させる", stem.u + "る", TypeIchidan, TypeKuru, "causative"},
+			Rule{stem.o + "よう", stem.u + "る", TypeAny, TypeKuru, "volitional"},
+			Rule{stem.o + "い", stem.u + "る", TypeAny, TypeKuru, "imperative"},
+			Rule{stem.i + "ている", stem.u + "る", TypeIchidan, TypeKuru, "progressive"},
+		)
+	}
+
+	// i-adjectives
+	rules = append(rules,
+		Rule{"く", "い", TypeAny, TypeAdjective, "adverbial"},
+		Rule{"くない", "い", TypeAdjective, TypeAdjective, "negative"},
+		Rule{"かった", "い", TypeAny, TypeAdjective, "past"},
+		Rule{"くて", "い", TypeAny, TypeAdjective, "te-form"},
+		Rule{"ければ", "い", TypeAny, TypeAdjective, "conditional (ba)"},
+		Rule{"かったら", "い", TypeAny, TypeAdjective, "conditional (tara)"},
+		Rule{"かろう", "い", TypeAny, TypeAdjective, "volitional"},
+		Rule{"くありません", "い", TypeAny, TypeAdjective, "polite negative"},
+		Rule{"くありませんでした", "い", TypeAny, TypeAdjective, "polite past negative"},
+		Rule{"さ", "い", TypeAny, TypeAdjective, "noun form"},
+		Rule{"そう", "い", TypeAny, TypeAdjective, "seemingness"},
+	)
+
+	return rules
+}
+
+// godanRules builds the rules for a row of godan verb endings, replacing
+// each inflected ending with the dictionary ending u
+func godanRules(row godanRow, u string, to Type) []Rule {
+	var rules []Rule
+	for _, e := range masuEndings {
+		rules = append(rules, Rule{row.i + e.tail, u, e.from, to, e.reason})
+	}
+	return append(rules,
+		Rule{row.a + "ない", u, TypeAdjective, to, "negative"},
+		Rule{row.te, u, TypeAny, to, "te-form"},
+		Rule{row.ta, u, TypeAny, to, "past"},
+		Rule{row.ta + "ら", u, TypeAny, to, "conditional (tara)"},
+		Rule{row.e + "ば", u, TypeAny, to, "conditional (ba)"},
+		Rule{row.e + "る", u, TypeIchidan, to, "potential"},
+		Rule{row.a + "れる", u, TypeIchidan, to, "passive"},
+		Rule{row.a + "せる", u, TypeIchidan, to, "causative"},
+		Rule{row.a + "せられる", u, TypeIchidan, to, "causative passive"},
+		Rule{row.o + "う", u, TypeAny, to, "volitional"},
+		Rule{row.e, u, TypeAny, to, "imperative"},
+		Rule{row.te + "いる", u, TypeIchidan, to, "progressive"},
+	)
+}
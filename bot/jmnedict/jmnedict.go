@@ -0,0 +1,113 @@
+// Package jmnedict loads JMnedict, the Japanese proper name dictionary
+package jmnedict
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+)
+
+// JMnedict is the decoded contents of a JMnedict file
+type JMnedict struct {
+	Entries []Entry `xml:"entry"`
+}
+
+// Entry is a name, which shares its kanji and reading elements with JMdict
+type Entry struct {
+	EntryID         string                  `xml:"ent_seq"`
+	KanjiElements   []jmdict.KanjiElement   `xml:"k_ele"`
+	ReadingElements []jmdict.ReadingElement `xml:"r_ele"`
+	Translations    []Translation           `xml:"trans"`
+}
+
+// Translation is the type and romanised or translated forms of a name
+type Translation struct {
+	NameTypes       []string `xml:"name_type"`
+	CrossReferences []string `xml:"xref"`
+	Details         []string `xml:"trans_det"`
+}
+
+// NameTypes maps the name_type entities of JMnedict to their descriptions
+var NameTypes = map[string]string{
+	"char":         "character",
+	"company":      "company name",
+	"creat":        "creature",
+	"dei":          "deity",
+	"doc":          "document",
+	"ev":           "event",
+	"fem":          "female given name or forename",
+	"fict":         "fiction",
+	"given":        "given name or forename, gender not specified",
+	"group":        "group",
+	"leg":          "legend",
+	"masc":         "male given name or forename",
+	"myth":         "mythology",
+	"obj":          "object",
+	"organization": "organization name",
+	"oth":          "other",
+	"person":       "full name of a particular person",
+	"place":        "place name",
+	"product":      "product name",
+	"relig":        "religion",
+	"serv":         "service",
+	"ship":         "ship name",
+	"station":      "railway station",
+	"surname":      "family or surname",
+	"unclass":      "unclassified name",
+	"work":         "work of art, literature, music, etc. name",
+}
+
+// entities holds every entity JMnedict may use, as its kanji and reading
+// elements can use the same entities as JMdict
+var entities = map[string]string{}
+
+func init() {
+	for code, description := range jmdict.Entities {
+		entities[code] = description
+	}
+	for code, description := range NameTypes {
+		entities[code] = description
+	}
+}
+
+// Load decodes a JMnedict file
+func Load(r io.Reader) (*JMnedict, error) {
+	n := &JMnedict{}
+	dec := xml.NewDecoder(r)
+	dec.Entity = entities
+	if err := dec.Decode(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// ToJMdict converts names to JMdict entries so they can be indexed and shown
+// like words. Each translation becomes a sense with its name types as misc
+// tags, and entry IDs are prefixed with "n" so they don't clash with JMdict
+func (n *JMnedict) ToJMdict() *jmdict.JMdict {
+	j := &jmdict.JMdict{Entries: make([]jmdict.Entry, len(n.Entries))}
+	for i, name := range n.Entries {
+		entry := jmdict.Entry{
+			EntryID:         "n" + name.EntryID,
+			KanjiElements:   name.KanjiElements,
+			ReadingElements: name.ReadingElements,
+		}
+		for _, translation := range name.Translations {
+			sense := jmdict.Sense{
+				Misc:            translation.NameTypes,
+				CrossReferences: translation.CrossReferences,
+			}
+			for _, detail := range translation.Details {
+				sense.GlossaryItems = append(
+					sense.GlossaryItems,
+					jmdict.Glossary{Definition: strings.TrimSpace(detail)},
+				)
+			}
+			entry.Senses = append(entry.Senses, sense)
+		}
+		j.Entries[i] = entry
+	}
+	return j
+}
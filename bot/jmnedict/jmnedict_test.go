@@ -0,0 +1,61 @@
+package jmnedict
+
+import (
+	"strings"
+	"testing"
+)
+
+const testJMnedict = `<?xml version="1.0" encoding="UTF-8"?>
+<JMnedict>
+<entry>
+<ent_seq>5000001</ent_seq>
+<k_ele><keb>田中</keb></k_ele>
+<r_ele><reb>たなか</reb></r_ele>
+<trans><name_type>&surname;</name_type><name_type>&place;</name_type><trans_det>Tanaka</trans_det></trans>
+</entry>
+<entry>
+<ent_seq>5000002</ent_seq>
+<r_ele><reb>さくら</reb></r_ele>
+<trans><name_type>&fem;</name_type><trans_det>Sakura</trans_det></trans>
+</entry>
+</JMnedict>`
+
+func TestLoad(t *testing.T) {
+	n, err := Load(strings.NewReader(testJMnedict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(n.Entries))
+	}
+
+	types := n.Entries[0].Translations[0].NameTypes
+	if strings.Join(types, ";") != "family or surname;place name" {
+		t.Errorf("expected surname and place name, got %v", types)
+	}
+	if types := n.Entries[1].Translations[0].NameTypes; types[0] != NameTypes["fem"] {
+		t.Errorf("expected fem to be a female given name, got %v", types)
+	}
+}
+
+func TestToJMdict(t *testing.T) {
+	n, err := Load(strings.NewReader(testJMnedict))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := n.ToJMdict()
+	entry := j.Entries[0]
+	if entry.EntryID != "n5000001" {
+		t.Errorf("expected ID n5000001, got %s", entry.EntryID)
+	}
+	if entry.KanjiElements[0].Phrase != "田中" || entry.ReadingElements[0].Phrase != "たなか" {
+		t.Errorf("expected 田中 (たなか), got %+v", entry)
+	}
+	if len(entry.Senses) != 1 || entry.Senses[0].GlossaryItems[0].Definition != "Tanaka" {
+		t.Errorf("expected a sense with Tanaka, got %+v", entry.Senses)
+	}
+	if len(entry.Senses[0].Misc) != 2 {
+		t.Errorf("expected the name types as misc tags, got %v", entry.Senses[0].Misc)
+	}
+}
@@ -0,0 +1,148 @@
+package kana
+
+import (
+	"strings"
+	"unicode"
+)
+
+// romajiInput maps romaji to hiragana, accepting both Hepburn and
+// Kunrei-shiki spellings
+var romajiInput = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"sa": "さ", "shi": "し", "si": "し", "su": "す", "se": "せ", "so": "そ",
+	"sha": "しゃ", "shu": "しゅ", "sho": "しょ", "she": "しぇ",
+	"sya": "しゃ", "syu": "しゅ", "syo": "しょ",
+	"ta": "た", "chi": "ち", "ti": "ち", "tsu": "つ", "tu": "つ", "te": "て", "to": "と",
+	"cha": "ちゃ", "chu": "ちゅ", "cho": "ちょ", "che": "ちぇ",
+	"tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+	"ha": "は", "hi": "ひ", "fu": "ふ", "hu": "ふ", "he": "へ", "ho": "ほ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+	"wa": "わ", "wi": "うぃ", "we": "うぇ", "wo": "を",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+	"za": "ざ", "ji": "じ", "zi": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"ja": "じゃ", "ju": "じゅ", "jo": "じょ", "je": "じぇ",
+	"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+	"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	"da": "だ", "di": "ぢ", "du": "づ", "dzu": "づ", "de": "で", "do": "ど",
+	"dya": "ぢゃ", "dyu": "ぢゅ", "dyo": "ぢょ",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+	"va": "ゔぁ", "vi": "ゔぃ", "vu": "ゔ", "ve": "ゔぇ", "vo": "ゔぉ",
+	"-": "ー",
+}
+
+// macronVowels maps vowels with macrons or circumflexes to the romaji
+// they are written as in kana
+var macronVowels = map[rune]string{
+	'ā': "aa", 'ī': "ii", 'ū': "uu", 'ē': "ei", 'ō': "ou",
+	'â': "aa", 'î': "ii", 'û': "uu", 'ê': "ei", 'ô': "ou",
+}
+
+// IsRomaji checks if s only contains characters which could be romaji
+func IsRomaji(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range strings.ToLower(s) {
+		if _, ok := macronVowels[r]; ok {
+			continue
+		}
+		if (r < 'a' || r > 'z') && r != '\'' && r != '-' && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// RomajiToHiragana transliterates romaji to hiragana, reporting false if
+// some of the text isn't valid romaji
+func RomajiToHiragana(s string) (string, bool) {
+	var expanded strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if long, isMacron := macronVowels[r]; isMacron {
+			expanded.WriteString(long)
+		} else {
+			expanded.WriteRune(r)
+		}
+	}
+
+	var (
+		builder strings.Builder
+		runes   = []rune(expanded.String())
+		ok      = true
+	)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			builder.WriteRune(r)
+			i++
+			continue
+		}
+
+		// n is a syllable on its own unless a vowel or y follows,
+		// and can be written nn or n' to be explicit
+		if r == 'n' {
+			if i+1 == len(runes) || !strings.ContainsRune("aiueoy", runes[i+1]) {
+				builder.WriteRune('ん')
+				i++
+				// nn before a vowel is ん followed by a syllable starting with n
+				if i < len(runes) && (runes[i] == '\'' ||
+					(runes[i] == 'n' && (i+1 == len(runes) || !strings.ContainsRune("aiueoy", runes[i+1])))) {
+					i++
+				}
+				continue
+			}
+		}
+		// a doubled consonant, or tch, is a small tsu
+		if i+1 < len(runes) && !strings.ContainsRune("aiueon'-", r) &&
+			(runes[i+1] == r || (r == 't' && runes[i+1] == 'c')) {
+			builder.WriteRune('っ')
+			i++
+			continue
+		}
+
+		matched := false
+		for length := 3; length > 0; length-- {
+			if i+length > len(runes) {
+				continue
+			}
+			if hiragana, found := romajiInput[string(runes[i:i+length])]; found {
+				builder.WriteString(hiragana)
+				i += length
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			builder.WriteRune(r)
+			ok = false
+			i++
+		}
+	}
+	return builder.String(), ok
+}
+
+// FromInput prepares text typed by a user for lookup, folding its width and
+// converting it to hiragana if it is written in romaji
+func FromInput(s string) string {
+	s = FoldWidth(s)
+	if IsRomaji(s) {
+		if hiragana, ok := RomajiToHiragana(s); ok {
+			return hiragana
+		}
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package kana
+
+import "strings"
+
+const (
+	katakanaStart = 'ァ'
+	katakanaEnd   = 'ヶ'
+	hiraganaStart = 'ぁ'
+	hiraganaEnd   = 'ゖ'
+	// kanaOffset is the distance between a katakana and its hiragana
+	kanaOffset = katakanaStart - hiraganaStart
+
+	longVowelMark = 'ー'
+)
+
+// halfWidthKatakana lists the katakana for U+FF66 to U+FF9D in order
+const halfWidthKatakana = "ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン"
+
+const (
+	halfWidthStart      = 'ｦ'
+	halfWidthDakuten    = 'ﾞ'
+	halfWidthHandakuten = 'ﾟ'
+)
+
+// vowels maps each hiragana to the vowel it ends with,
+// used to expand long vowel marks
+var vowels = map[rune]rune{}
+
+func init() {
+	groups := map[rune]string{
+		'あ': "あぁかがさざただなはばぱまやゃらわゎ",
+		'い': "いぃきぎしじちぢにひびぴみりゐ",
+		'う': "うぅくぐすずつづぬふぶぷむゆゅるゔ",
+		'え': "えぇけげせぜてでねへべぺめれゑ",
+		'お': "おぉこごそぞとどのほぼぽもよょろを",
+	}
+	for vowel, group := range groups {
+		for _, r := range group {
+			vowels[r] = vowel
+		}
+	}
+}
+
+// IsHiragana checks if a rune is hiragana
+func IsHiragana(r rune) bool {
+	return r >= hiraganaStart && r <= hiraganaEnd
+}
+
+// IsKatakana checks if a rune is katakana
+func IsKatakana(r rune) bool {
+	return r >= katakanaStart && r <= katakanaEnd
+}
+
+// ToHiragana converts any katakana in s to hiragana
+func ToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if IsKatakana(r) {
+			return r - kanaOffset
+		}
+		return r
+	}, s)
+}
+
+// ToKatakana converts any hiragana in s to katakana
+func ToKatakana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if IsHiragana(r) {
+			return r + kanaOffset
+		}
+		return r
+	}, s)
+}
+
+// FoldWidth converts full width ASCII to ASCII and half width katakana to
+// full width, as NFKC normalisation would for Japanese text
+func FoldWidth(s string) string {
+	var (
+		out      = make([]rune, 0, len(s))
+		halfKana = []rune(halfWidthKatakana)
+	)
+	for _, r := range s {
+		switch {
+		case r >= '！' && r <= '～':
+			out = append(out, r-0xfee0)
+		case r == '　':
+			out = append(out, ' ')
+		case r >= halfWidthStart && r < halfWidthStart+rune(len(halfKana)):
+			out = append(out, halfKana[r-halfWidthStart])
+		case r == halfWidthDakuten || r == halfWidthHandakuten:
+			// combine with the previous kana where possible
+			if len(out) > 0 {
+				if c, ok := combineMark(out[len(out)-1], r == halfWidthHandakuten); ok {
+					out[len(out)-1] = c
+					continue
+				}
+			}
+			if r == halfWidthDakuten {
+				out = append(out, '゛')
+			} else {
+				out = append(out, '゜')
+			}
+		default:
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+// combineMark adds a dakuten or handakuten to a katakana
+func combineMark(r rune, handakuten bool) (rune, bool) {
+	switch {
+	case r == 'ウ' && !handakuten:
+		return 'ヴ', true
+	case handakuten && strings.ContainsRune("ハヒフヘホ", r):
+		return r + 2, true
+	case !handakuten && strings.ContainsRune("カキクケコサシスセソタチツテトハヒフヘホ", r):
+		return r + 1, true
+	}
+	return r, false
+}
+
+// FoldRunes folds katakana to hiragana and expands long vowel marks to the
+// vowel they lengthen. Each rune maps to exactly one rune so positions in
+// the result match positions in the input
+func FoldRunes(runes []rune) []rune {
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		if IsKatakana(r) {
+			r -= kanaOffset
+		}
+		if r == longVowelMark && i > 0 {
+			if vowel, ok := vowels[folded[i-1]]; ok {
+				r = vowel
+			}
+		}
+		folded[i] = r
+	}
+	return folded
+}
+
+// Normalize folds text so that the same word written in hiragana, katakana,
+// half width katakana or with long vowel marks gives the same result
+func Normalize(s string) string {
+	return string(FoldRunes([]rune(FoldWidth(s))))
+}
@@ -0,0 +1,82 @@
+package kana
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"タベル":   "たべる",
+		"ｶﾀｶﾅ":  "かたかな",
+		"ｶﾞｯｺｳ": "がっこう",
+		"ﾊﾟﾝ":   "ぱん",
+		"ＡＢＣ":   "ABC",
+		"ラーメン":  "らあめん",
+		"食べる":   "食べる",
+		"コーヒー":  "こおひい",
+		"ﾗｰﾒﾝ":  "らあめん",
+		"すごーーい": "すごおおい",
+	}
+	for in, expected := range cases {
+		if result := Normalize(in); result != expected {
+			t.Errorf("%s: expected %s, got %s", in, expected, result)
+		}
+	}
+}
+
+func TestRomajiToHiragana(t *testing.T) {
+	cases := map[string]string{
+		"taberu":     "たべる",
+		"konnichiha": "こんにちは",
+		"kon'ya":     "こんや",
+		"shinbun":    "しんぶん",
+		"sinbun":     "しんぶん",
+		"gakkou":     "がっこう",
+		"matcha":     "まっちゃ",
+		"tōkyō":      "とうきょう",
+		"Tsukue":     "つくえ",
+		"tukue":      "つくえ",
+		"jisho":      "じしょ",
+		"zisyo":      "じしょ",
+		"ra-men":     "らーめん",
+	}
+	for in, expected := range cases {
+		result, ok := RomajiToHiragana(in)
+		if !ok || result != expected {
+			t.Errorf("%s: expected %s, got %s (%v)", in, expected, result, ok)
+		}
+	}
+
+	if _, ok := RomajiToHiragana("xyz"); ok {
+		t.Error("expected xyz to be invalid")
+	}
+}
+
+func TestToRomaji(t *testing.T) {
+	cases := []struct {
+		in       string
+		opts     RomajiOptions
+		expected string
+	}{
+		{"しんぶん", RomajiOptions{}, "shinbun"},
+		{"こんや", RomajiOptions{}, "kon'ya"},
+		{"がっこう", RomajiOptions{}, "gakkō"},
+		{"がっこう", RomajiOptions{Doubled: true}, "gakkou"},
+		{"まっちゃ", RomajiOptions{}, "matcha"},
+		{"とうきょう", RomajiOptions{}, "tōkyō"},
+		{"ラーメン", RomajiOptions{}, "rāmen"},
+		{"ラーメン", RomajiOptions{Doubled: true}, "raamen"},
+		{"おにいさん", RomajiOptions{}, "oniisan"},
+		{"フォーク", RomajiOptions{}, "fōku"},
+		{"じしょ", RomajiOptions{Style: Kunrei}, "zisyo"},
+		{"つくえ", RomajiOptions{Style: Kunrei}, "tukue"},
+		{"とうきょう", RomajiOptions{Style: Kunrei}, "tôkyô"},
+		{"ちぢむ", RomajiOptions{Style: Kunrei}, "tizimu"},
+		{"ちぢむ", RomajiOptions{Style: Nihon}, "tidimu"},
+		{"を", RomajiOptions{Style: Nihon}, "wo"},
+		{"食べる", RomajiOptions{}, "食beru"},
+	}
+	for _, c := range cases {
+		if result := ToRomaji(c.in, c.opts); result != c.expected {
+			t.Errorf("%s (%s): expected %s, got %s", c.in, c.opts.Style, c.expected, result)
+		}
+	}
+}
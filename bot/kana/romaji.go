@@ -0,0 +1,251 @@
+package kana
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style is a system of romanisation
+type Style int
+
+const (
+	// Hepburn is modified Hepburn romanisation, e.g. shi, tsu, fu
+	Hepburn Style = iota
+	// Kunrei is Kunrei-shiki romanisation, e.g. si, tu, hu
+	Kunrei
+	// Nihon is Nihon-shiki romanisation, which also keeps di, du and wo
+	Nihon
+)
+
+var styleNames = map[string]Style{
+	"hepburn": Hepburn,
+	"kunrei":  Kunrei,
+	"nihon":   Nihon,
+}
+
+// ParseStyle parses the name of a romanisation style
+func ParseStyle(name string) (Style, error) {
+	if style, ok := styleNames[strings.ToLower(name)]; ok {
+		return style, nil
+	}
+	return Hepburn, fmt.Errorf("%s isn't a romanisation style", name)
+}
+
+// String returns the name of the style
+func (s Style) String() string {
+	for name, style := range styleNames {
+		if style == s {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// RomajiOptions configures how kana is romanised
+type RomajiOptions struct {
+	Style Style
+	// Doubled writes long vowels as doubled letters instead of with
+	// macrons, or circumflexes for Kunrei and Nihon-shiki
+	Doubled bool
+}
+
+// romaji holds the Hepburn romanisation of each hiragana
+var romaji = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// styleRomaji overrides the Hepburn romanisation for other styles
+var styleRomaji = map[Style]map[rune]string{
+	Kunrei: {
+		'し': "si", 'ち': "ti", 'つ': "tu", 'ふ': "hu", 'じ': "zi", 'ぢ': "zi",
+	},
+	Nihon: {
+		'し': "si", 'ち': "ti", 'つ': "tu", 'ふ': "hu", 'じ': "zi", 'ぢ': "di",
+		'づ': "du", 'を': "wo",
+	},
+}
+
+// yoonStems holds the consonants used before a small ya, yu or yo,
+// where they differ from the romanisation minus its vowel
+var yoonStems = map[Style]map[rune]string{
+	Hepburn: {'し': "sh", 'ち': "ch", 'じ': "j", 'ぢ': "j"},
+	Kunrei:  {'し': "sy", 'ち': "ty", 'じ': "zy", 'ぢ': "zy"},
+	Nihon:   {'し': "sy", 'ち': "ty", 'じ': "zy", 'ぢ': "dy"},
+}
+
+var punctuation = map[rune]string{
+	'。': ".", '、': ",", '・': " ", '「': "\"", '」': "\"", '！': "!", '？': "?",
+}
+
+var (
+	macrons      = map[byte]string{'a': "ā", 'i': "ī", 'u': "ū", 'e': "ē", 'o': "ō"}
+	circumflexes = map[byte]string{'a': "â", 'i': "î", 'u': "û", 'e': "ê", 'o': "ô"}
+)
+
+// ToRomaji romanises the kana in s, leaving anything else as it is
+func ToRomaji(s string, opts RomajiOptions) string {
+	var (
+		syllables []string
+		runes     = []rune(ToHiragana(s))
+	)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case r == 'っ':
+			syllables = append(syllables, "っ")
+		case r == longVowelMark:
+			syllables = append(syllables, "ー")
+		case isYoon(next) && isIStem(r):
+			syllables = append(syllables, yoonStem(r, opts.Style)+romaji[next][1:])
+			i++
+		case isSmallVowel(next) && romaji[r] != "":
+			// extended katakana such as ファ and ティ
+			syllables = append(syllables, consonant(r)+romaji[next])
+			i++
+		default:
+			if syllable, ok := syllableRomaji(r, opts.Style); ok {
+				syllables = append(syllables, syllable)
+			} else if p, ok := punctuation[r]; ok {
+				syllables = append(syllables, p)
+			} else {
+				syllables = append(syllables, string(r))
+			}
+		}
+	}
+
+	return joinSyllables(syllables, opts)
+}
+
+func syllableRomaji(r rune, style Style) (string, bool) {
+	if override, ok := styleRomaji[style][r]; ok {
+		return override, true
+	}
+	syllable, ok := romaji[r]
+	return syllable, ok
+}
+
+// joinSyllables resolves small tsu, syllabic n and long vowels,
+// which depend on the syllables around them
+func joinSyllables(syllables []string, opts RomajiOptions) string {
+	var builder strings.Builder
+	lastVowel := byte(0)
+	for i, syllable := range syllables {
+		next := ""
+		if i+1 < len(syllables) {
+			next = syllables[i+1]
+		}
+
+		switch {
+		case syllable == "っ":
+			// double the next consonant, or t before ch
+			if strings.HasPrefix(next, "ch") {
+				builder.WriteByte('t')
+			} else if next != "" && !isVowel(next[0]) && isLetter(next[0]) {
+				builder.WriteByte(next[0])
+			}
+			lastVowel = 0
+			continue
+		case syllable == "ー":
+			if lastVowel != 0 {
+				lengthen(&builder, lastVowel, opts)
+			}
+			lastVowel = 0
+			continue
+		case syllable == "n" && next != "" && (isVowel(next[0]) || next[0] == 'y'):
+			builder.WriteString("n'")
+			lastVowel = 0
+			continue
+		}
+
+		// merge aa, uu, ee, oo and ou into a long vowel unless doubled is set
+		if !opts.Doubled && len(syllable) == 1 && lastVowel != 0 &&
+			(syllable[0] == lastVowel && lastVowel != 'i' || lastVowel == 'o' && syllable == "u") {
+			lengthen(&builder, lastVowel, opts)
+			lastVowel = 0
+			continue
+		}
+
+		builder.WriteString(syllable)
+		lastVowel = 0
+		if last := syllable[len(syllable)-1]; isVowel(last) {
+			lastVowel = last
+		}
+	}
+	return builder.String()
+}
+
+// lengthen marks the vowel just written as long
+func lengthen(builder *strings.Builder, vowel byte, opts RomajiOptions) {
+	if opts.Doubled {
+		builder.WriteByte(vowel)
+		return
+	}
+
+	marks := macrons
+	if opts.Style != Hepburn {
+		marks = circumflexes
+	}
+	written := builder.String()
+	builder.Reset()
+	builder.WriteString(written[:len(written)-1])
+	builder.WriteString(marks[vowel])
+}
+
+func isYoon(r rune) bool {
+	return r == 'ゃ' || r == 'ゅ' || r == 'ょ'
+}
+
+func isSmallVowel(r rune) bool {
+	return r == 'ぁ' || r == 'ぃ' || r == 'ぅ' || r == 'ぇ' || r == 'ぉ'
+}
+
+// isIStem checks if a kana is in the i column, which combines with small ya, yu and yo
+func isIStem(r rune) bool {
+	return strings.ContainsRune("きしちにひみりぎじぢびぴ", r)
+}
+
+func yoonStem(r rune, style Style) string {
+	if stem, ok := yoonStems[style][r]; ok {
+		return stem
+	}
+	return consonant(r) + "y"
+}
+
+// consonant returns the Hepburn romanisation of a kana without its vowel
+func consonant(r rune) string {
+	if r == 'う' {
+		return "w"
+	}
+	syllable := romaji[r]
+	return strings.TrimRight(syllable, "aiueo")
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aiueo", b) != -1
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
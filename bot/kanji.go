@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+const (
+	// maxKanjiPerRequest is the number of kanji jpn!kanji will describe at once
+	maxKanjiPerRequest = 5
+	// maxKanjiWords is the number of common words shown for a kanji
+	maxKanjiWords = 8
+)
+
+func (b *JapanBot) kanji(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if b.kanjidic == nil {
+		s.ChannelMessageSend(m.ChannelID, "Kanji information isn't available, sorry!")
+		return
+	}
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a kanji!")
+		return
+	}
+
+	var characters []string
+	for _, r := range strings.Join(args[1:], "") {
+		if unicode.Is(unicode.Han, r) && len(characters) < maxKanjiPerRequest {
+			characters = append(characters, string(r))
+		}
+	}
+	if len(characters) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "That doesn't contain any kanji!")
+		return
+	}
+
+	for _, character := range characters {
+		kanji, ok := b.kanjidic.Characters[character]
+		if !ok {
+			s.ChannelMessageSend(
+				m.ChannelID,
+				fmt.Sprintf("I don't know the kanji %s!", character),
+			)
+			continue
+		}
+		s.ChannelMessageSend(m.ChannelID, b.buildKanjiResponse(kanji))
+	}
+}
+
+func (b *JapanBot) buildKanjiResponse(kanji *dictionary.Kanji) string {
+	var message strings.Builder
+	writeLine := func(label string, values []string) {
+		if len(values) > 0 {
+			message.WriteString(
+				fmt.Sprintf("%s%s\n", label, strings.Join(values, ", ")),
+			)
+		}
+	}
+
+	message.WriteString(fmt.Sprintf("```\n%s\n\n", kanji.Literal))
+	writeLine("On: ", kanji.OnReadings())
+	writeLine("Kun: ", kanji.KunReadings())
+	writeLine("Nanori: ", kanji.Nanori)
+	writeLine("Meanings: ", kanji.MeaningsIn("en"))
+	message.WriteString("\n")
+
+	var stats []string
+	if strokes := kanji.StrokeCount(); strokes > 0 {
+		stats = append(stats, fmt.Sprintf("Strokes: %d", strokes))
+	}
+	if kanji.Grade > 0 {
+		stats = append(stats, fmt.Sprintf("Grade: %d", kanji.Grade))
+	}
+	if kanji.JLPT > 0 {
+		stats = append(stats, fmt.Sprintf("JLPT: %d (old levels)", kanji.JLPT))
+	}
+	if kanji.Frequency > 0 {
+		stats = append(stats, fmt.Sprintf("Frequency rank: %d", kanji.Frequency))
+	}
+	if len(stats) > 0 {
+		message.WriteString(strings.Join(stats, " | ") + "\n")
+	}
+
+	words := b.dictionary().CommonWordsContaining(kanji.Literal, maxKanjiWords)
+	writeLine("Common words: ", words)
+	message.WriteString("```")
+	return message.String()
+}
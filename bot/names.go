@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"io"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/jmnedict"
+)
+
+// maxNameResults is the number of names shown by jpn!name
+const maxNameResults = 10
+
+// namesSource describes JMnedict, which is only used for words JMdict doesn't have
+var namesSource = dictionary.SourceInfo{
+	Name:        "JMnedict",
+	Description: "proper name",
+	Mark:        "◆ ",
+	Fallback:    true,
+}
+
+func loadNames(file string) (*dictionary.Dictionary, error) {
+	names, err := dictionary.OpenCached(file, func(r io.Reader) (*jmdict.JMdict, error) {
+		n, err := jmnedict.Load(r)
+		if err != nil {
+			return nil, err
+		}
+		return n.ToJMdict(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names.WithInfo(namesSource), nil
+}
+
+func (b *JapanBot) name(args []string, s *discordgo.Session, m *discordgo.Message) {
+	names := b.sources().Named(namesSource.Name)
+	if names == nil {
+		s.ChannelMessageSend(m.ChannelID, "The names dictionary isn't available, sorry!")
+		return
+	}
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a name!")
+		return
+	}
+
+	entries := names.Lookup(strings.Join(args[1:], ""))
+	if len(entries) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No names found :(")
+		return
+	}
+	if len(entries) > maxNameResults {
+		entries = entries[:maxNameResults]
+	}
+
+	var results []dictionary.Result
+	for _, entry := range entries {
+		results = append(results, dictionary.Result{Entry: entry, Source: names.Info()})
+	}
+	response := b.buildResultsResponse(results, &definitionOptions{}, m)
+	for _, r := range strings.Split(response, "--") {
+		s.ChannelMessageSend(m.ChannelID, r)
+	}
+}
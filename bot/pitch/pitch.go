@@ -0,0 +1,154 @@
+// Package pitch loads pitch accent data and draws accent contours
+package pitch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// Accent is the pitch accent of a reading
+type Accent struct {
+	// Downstep is the mora after which the pitch falls,
+	// 0 if it doesn't fall within the word
+	Downstep int
+	// Note qualifies the accent, such as the part of speech it applies to
+	Note string
+}
+
+// Accents holds accents by headword and reading
+type Accents struct {
+	accents map[string][]Accent
+}
+
+// accentPattern matches an accent such as 2 or (副)0
+var accentPattern = regexp.MustCompile(`^(?:\((.+?)\))?(\d+)$`)
+
+// Load loads a tab separated accent file of headword, reading and
+// comma separated accents, such as the Kanjium accents.txt.
+// The reading can be blank for words written in kana
+func Load(r io.Reader) (*Accents, error) {
+	a := &Accents{accents: make(map[string][]Accent)}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line %d: expected headword, reading and accents", lineNumber)
+		}
+		headword, reading := fields[0], fields[1]
+		if reading == "" {
+			reading = headword
+		}
+
+		var accents []Accent
+		for _, part := range strings.Split(fields[2], ",") {
+			match := accentPattern.FindStringSubmatch(strings.TrimSpace(part))
+			if match == nil {
+				return nil, fmt.Errorf("line %d: %s isn't an accent", lineNumber, part)
+			}
+			downstep, _ := strconv.Atoi(match[2])
+			accents = append(accents, Accent{Downstep: downstep, Note: match[1]})
+		}
+		key := accentKey(headword, reading)
+		a.accents[key] = append(a.accents[key], accents...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func accentKey(headword, reading string) string {
+	return headword + "\t" + kana.ToHiragana(reading)
+}
+
+// Lookup finds the accents of a headword read a certain way
+func (a *Accents) Lookup(headword, reading string) []Accent {
+	return a.accents[accentKey(headword, reading)]
+}
+
+// Len returns the number of headword and reading pairs with accents
+func (a *Accents) Len() int {
+	return len(a.accents)
+}
+
+// smallKana combine with the kana before them into one mora
+const smallKana = "ゃゅょぁぃぅぇぉゎャュョァィゥェォヮ"
+
+// Morae splits a reading into morae
+func Morae(reading string) []string {
+	var morae []string
+	for _, r := range reading {
+		if len(morae) > 0 && strings.ContainsRune(smallKana, r) {
+			morae[len(morae)-1] += string(r)
+			continue
+		}
+		morae = append(morae, string(r))
+	}
+	return morae
+}
+
+// Pattern names the accent pattern of a reading with a downstep
+func Pattern(reading string, downstep int) string {
+	switch {
+	case downstep == 0:
+		return "heiban"
+	case downstep == 1:
+		return "atamadaka"
+	case downstep >= len(Morae(reading)):
+		return "odaka"
+	}
+	return "nakadaka"
+}
+
+// High reports the pitch of each mora of a reading, true being high.
+// The first mora is low unless the downstep follows it, and the pitch
+// stays high from the second mora until the downstep
+func High(reading string, downstep int) []bool {
+	morae := Morae(reading)
+	high := make([]bool, len(morae))
+	for i := range high {
+		switch {
+		case downstep == 1:
+			high[i] = i == 0
+		case i == 0:
+			high[i] = false
+		default:
+			high[i] = downstep == 0 || i < downstep
+		}
+	}
+	return high
+}
+
+// Contour draws the pitch of a reading as a line of ￣ and ＿ to be shown
+// above the reading, one character wide for each kana. A fall after the
+// last mora, onto a following particle, is drawn as ＼
+func Contour(reading string, downstep int) string {
+	var (
+		builder strings.Builder
+		morae   = Morae(reading)
+	)
+	for i, high := range High(reading, downstep) {
+		mark := "＿"
+		if high {
+			mark = "￣"
+		}
+		builder.WriteString(strings.Repeat(mark, len([]rune(morae[i]))))
+	}
+	if downstep > 0 && downstep == len(morae) {
+		builder.WriteString("＼")
+	}
+	return builder.String()
+}
@@ -0,0 +1,62 @@
+package pitch
+
+import (
+	"strings"
+	"testing"
+)
+
+const testAccents = `日本	にほん	2
+猫	ねこ	1
+男	おとこ	3
+鶏	にわとり	0
+今日	きょう	1
+副	ふく	(名)2,(副)0
+ああ		0
+`
+
+func TestLoad(t *testing.T) {
+	a, err := Load(strings.NewReader(testAccents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 7 {
+		t.Errorf("expected 7 readings, got %d", a.Len())
+	}
+	if accents := a.Lookup("日本", "ニホン"); len(accents) != 1 || accents[0].Downstep != 2 {
+		t.Errorf("expected [2] for 日本, got %v", accents)
+	}
+	accents := a.Lookup("副", "ふく")
+	if len(accents) != 2 || accents[0].Note != "名" || accents[1].Downstep != 0 {
+		t.Errorf("expected (名)2 and (副)0, got %v", accents)
+	}
+	if accents := a.Lookup("ああ", "ああ"); len(accents) != 1 {
+		t.Errorf("expected a kana headword to be its own reading, got %v", accents)
+	}
+
+	if _, err := Load(strings.NewReader("日本\tにほん\tx\n")); err == nil {
+		t.Error("expected an error for an invalid accent")
+	}
+}
+
+func TestContour(t *testing.T) {
+	cases := []struct {
+		reading  string
+		downstep int
+		contour  string
+		pattern  string
+	}{
+		{"にほん", 2, "＿￣＿", "nakadaka"},
+		{"ねこ", 1, "￣＿", "atamadaka"},
+		{"おとこ", 3, "＿￣￣＼", "odaka"},
+		{"にわとり", 0, "＿￣￣￣", "heiban"},
+		{"きょう", 1, "￣￣＿", "atamadaka"},
+	}
+	for _, c := range cases {
+		if contour := Contour(c.reading, c.downstep); contour != c.contour {
+			t.Errorf("%s [%d]: expected %s, got %s", c.reading, c.downstep, c.contour, contour)
+		}
+		if pattern := Pattern(c.reading, c.downstep); pattern != c.pattern {
+			t.Errorf("%s [%d]: expected %s, got %s", c.reading, c.downstep, c.pattern, pattern)
+		}
+	}
+}
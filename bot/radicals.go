@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+// maxComponentResults is the number of kanji shown by jpn!bycomponent
+const maxComponentResults = 60
+
+func loadRadicals(kradFile, radkFile string) (*dictionary.Radicals, error) {
+	krad, err := os.Open(kradFile)
+	if err != nil {
+		return nil, err
+	}
+	defer krad.Close()
+
+	radk, err := os.Open(radkFile)
+	if err != nil {
+		return nil, err
+	}
+	defer radk.Close()
+
+	return dictionary.LoadRadicals(krad, radk)
+}
+
+func (b *JapanBot) radicals(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if b.radicalFiles == nil {
+		s.ChannelMessageSend(m.ChannelID, "Radical information isn't available, sorry!")
+		return
+	}
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a kanji!")
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString("```\n")
+	for _, r := range strings.Join(args[1:], "") {
+		if !unicode.Is(unicode.Han, r) {
+			continue
+		}
+		components, ok := b.radicalFiles.Components[string(r)]
+		if !ok {
+			message.WriteString(fmt.Sprintf("%s: unknown\n", string(r)))
+			continue
+		}
+		var parts []string
+		for _, component := range components {
+			parts = append(
+				parts,
+				fmt.Sprintf("%s (%d)", dictionary.ComponentForm(component), b.radicalFiles.Strokes[component]),
+			)
+		}
+		message.WriteString(fmt.Sprintf("%s: %s\n", string(r), strings.Join(parts, " ")))
+	}
+	message.WriteString("```")
+
+	if message.Len() == len("```\n```") {
+		s.ChannelMessageSend(m.ChannelID, "That doesn't contain any kanji!")
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, message.String())
+}
+
+// bycomponent handles jpn!bycomponent 口 木 [strokes], where strokes is
+// a number or range such as 5-8
+func (b *JapanBot) bycomponent(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if b.radicalFiles == nil {
+		s.ChannelMessageSend(m.ChannelID, "Radical information isn't available, sorry!")
+		return
+	}
+
+	var (
+		components []string
+		minStrokes = 0
+		maxStrokes = 0
+	)
+	for _, arg := range args[1:] {
+		if arg == "" {
+			continue
+		}
+		if low, high, ok := parseStrokeRange(arg); ok {
+			minStrokes, maxStrokes = low, high
+			continue
+		}
+		for _, r := range arg {
+			components = append(components, string(r))
+		}
+	}
+	if len(components) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered any components!")
+		return
+	}
+	if maxStrokes > 0 && b.kanjidic == nil {
+		s.ChannelMessageSend(m.ChannelID, "Stroke counts aren't available, sorry!")
+		return
+	}
+
+	var result []string
+	for _, kanji := range b.radicalFiles.KanjiWith(components) {
+		if maxStrokes > 0 {
+			strokes := b.kanjiStrokes(kanji)
+			if strokes < minStrokes || strokes > maxStrokes {
+				continue
+			}
+		}
+		result = append(result, kanji)
+	}
+	if len(result) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No kanji contain all of those components :(")
+		return
+	}
+
+	// simplest kanji first
+	sort.SliceStable(result, func(i, j int) bool {
+		return b.kanjiStrokes(result[i]) < b.kanjiStrokes(result[j])
+	})
+	more := ""
+	if len(result) > maxComponentResults {
+		more = fmt.Sprintf("\n...and %d more, add a stroke count to narrow it down", len(result)-maxComponentResults)
+		result = result[:maxComponentResults]
+	}
+	s.ChannelMessageSend(
+		m.ChannelID,
+		fmt.Sprintf("```\n%s%s\n```", strings.Join(result, " "), more),
+	)
+}
+
+// kanjiStrokes returns the stroke count of a kanji, 0 if it isn't known
+func (b *JapanBot) kanjiStrokes(kanji string) int {
+	if b.kanjidic == nil {
+		return 0
+	}
+	if k, ok := b.kanjidic.Characters[kanji]; ok {
+		return k.StrokeCount()
+	}
+	return 0
+}
+
+// parseStrokeRange parses a stroke count such as 7 or a range such as 5-8
+func parseStrokeRange(arg string) (int, int, bool) {
+	parts := strings.SplitN(arg, "-", 2)
+	low, err := strconv.Atoi(parts[0])
+	if err != nil || low < 1 {
+		return 0, 0, false
+	}
+	if len(parts) == 1 {
+		return low, low, true
+	}
+	high, err := strconv.Atoi(parts[1])
+	if err != nil || high < low {
+		return 0, 0, false
+	}
+	return low, high, true
+}
@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+func (b *JapanBot) see(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if len(args) != 2 {
+		s.ChannelMessageSend(m.ChannelID, "You need to enter the number of a reference!")
+		return
+	}
+
+	selection, err := strconv.Atoi(args[1])
+	refs := b.userReferences(m.Author.ID)
+	if err != nil || selection < 1 || selection > len(refs) {
+		s.ChannelMessageSend(m.ChannelID, "Reference number is invalid!")
+		return
+	}
+
+	ref := refs[selection-1]
+	results := b.sources().Resolve(ref)
+	if len(results) == 0 {
+		s.ChannelMessageSend(
+			m.ChannelID,
+			fmt.Sprintf("Couldn't find %s in the dictionary!", ref),
+		)
+		return
+	}
+
+	response := b.buildResultsResponse(results, &definitionOptions{sense: ref.Sense}, m)
+	for _, r := range strings.Split(response, "--") {
+		s.ChannelMessageSend(m.ChannelID, r)
+	}
+}
+
+// userReferences returns the cross-references last shown to a user
+func (b *JapanBot) userReferences(userID string) []dictionary.Reference {
+	b.requestsLock.RLock()
+	defer b.requestsLock.RUnlock()
+	return b.references[userID]
+}
+
+// setReferences sets the cross-references shown to a user, to be followed by number
+func (b *JapanBot) setReferences(userID string, refs []dictionary.Reference) {
+	b.requestsLock.Lock()
+	b.references[userID] = refs
+	b.requestsLock.Unlock()
+}
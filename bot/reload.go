@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/database/store"
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+const (
+	// maxAnnouncedEntries is the most entries of each kind announced
+	// after a reload
+	maxAnnouncedEntries = 10
+	// maxAnnouncementLength keeps announcements under Discord's limit
+	// of 2000 characters a message
+	maxAnnouncementLength = 1900
+)
+
+// ErrReloading is returned when the dictionary is already being reloaded
+var ErrReloading = errors.New("the dictionary is already being reloaded")
+
+func (b *JapanBot) reload(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if !b.isOwner(m.Author.ID) {
+		s.ChannelMessageSend(m.ChannelID, "Only the bot's owners can do that!")
+		return
+	}
+	if len(args) != 2 || strings.ToLower(args[1]) != "dictionary" {
+		s.ChannelMessageSend(m.ChannelID, "Use jpn!reload dictionary")
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, "Reloading the dictionary, this takes a while...")
+	go func() {
+		if err := b.ReloadDictionary(); err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("That failed: %s", err.Error()))
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "Done :)")
+	}()
+}
+
+// isOwner checks if a user is one of the owners in the config
+func (b *JapanBot) isOwner(userID string) bool {
+	for _, id := range b.configuration.OwnerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// dictionaries returns the dictionaries currently loaded. Commands which
+// use more than one take them once, so a reload part way through doesn't
+// mix the dictionaries of two loads
+func (b *JapanBot) dictionaries() *dictionaries {
+	b.dictsLock.RLock()
+	defer b.dictsLock.RUnlock()
+	return b.dicts
+}
+
+// dictionary returns the JMdict dictionary currently loaded
+func (b *JapanBot) dictionary() dictionary.Words {
+	b.dictsLock.RLock()
+	defer b.dictsLock.RUnlock()
+	return b.dicts.dictionary
+}
+
+// sources returns the sources currently loaded
+func (b *JapanBot) sources() *dictionary.Sources {
+	b.dictsLock.RLock()
+	defer b.dictsLock.RUnlock()
+	return b.dicts.sources
+}
+
+// ReloadDictionary loads the JMdict file and the configured sources again,
+// replacing the loaded ones once they're ready. Commands keep using the
+// old dictionaries until then
+func (b *JapanBot) ReloadDictionary() error {
+	if !atomic.CompareAndSwapInt32(&b.reloading, 0, 1) {
+		return ErrReloading
+	}
+	defer atomic.StoreInt32(&b.reloading, 0)
+
+	files, err := findDictionaryFiles(b.configuration)
+	if err != nil {
+		return err
+	}
+	dicts, err := loadDictionaries(files, b.db)
+	if err != nil {
+		return err
+	}
+
+	b.dictsLock.Lock()
+	old := b.dicts
+	b.dicts = dicts
+	b.dictsLock.Unlock()
+	fmt.Println("Reloaded the dictionary")
+
+	if b.configuration.AnnouncementsChannel != "" {
+		diff, err := compareDictionaries(old.dictionary, dicts.dictionary)
+		if err != nil {
+			fmt.Printf("Error comparing dictionaries: %s\n", err.Error())
+		} else {
+			b.announceChanges(diff)
+		}
+	}
+	return nil
+}
+
+// compareDictionaries compares the JMdict entries of two loads, which are
+// either both held in memory or both kept in the database
+func compareDictionaries(before, after dictionary.Words) (*dictionary.Diff, error) {
+	switch after := after.(type) {
+	case *dictionary.Dictionary:
+		if before, ok := before.(*dictionary.Dictionary); ok {
+			return dictionary.Compare(before, after), nil
+		}
+	case *store.Store:
+		if before, ok := before.(*store.Store); ok {
+			return after.Compare(before)
+		}
+	}
+	return nil, errors.New("the dictionary store changed, so the dictionaries can't be compared")
+}
+
+// announceChanges posts the words which changed in a reload to the
+// announcements channel
+func (b *JapanBot) announceChanges(diff *dictionary.Diff) {
+	if diff.Empty() || b.session == nil {
+		return
+	}
+
+	var report strings.Builder
+	diff.Write(&report, maxAnnouncedEntries)
+	text := report.String()
+	if len(text) > maxAnnouncementLength {
+		text = text[:strings.LastIndex(text[:maxAnnouncementLength], "\n")+1] + "...\n"
+	}
+	_, err := b.session.ChannelMessageSend(
+		b.configuration.AnnouncementsChannel,
+		fmt.Sprintf("```\nNew in the dictionary!\n\n%s```", text),
+	)
+	if err != nil {
+		fmt.Printf("Error announcing dictionary changes: %s\n", err.Error())
+	}
+}
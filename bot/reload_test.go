@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hakasec/japanbot-go/bot/config"
+	"github.com/hakasec/japanbot-go/bot/database"
+)
+
+const testJMdict = `<JMdict>
+<entry><ent_seq>1</ent_seq><k_ele><keb>猫</keb></k_ele><r_ele><reb>ねこ</reb></r_ele><sense><gloss>cat</gloss></sense></entry>
+<entry><ent_seq>2</ent_seq><k_ele><keb>犬</keb></k_ele><r_ele><reb>いぬ</reb></r_ele><sense><gloss>dog</gloss></sense></entry>
+</JMdict>`
+
+// loadTestDictionaries writes a JMdict file and loads it as a reload would
+func loadTestDictionaries(t *testing.T, files *config.BotConfiguration, db *database.DBConnection, contents string) *dictionaries {
+	if err := ioutil.WriteFile(files.JMdictFile, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dicts, err := loadDictionaries(files, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dicts
+}
+
+func TestCompareReloads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := database.OpenFromConfig(&config.DBConfiguration{
+		DriverName: "sqlite3",
+		ConnString: ":memory:",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+
+	updated := strings.Replace(testJMdict, "<gloss>cat</gloss>", "<gloss>cat (animal)</gloss>", 1)
+	for _, dictionaryStore := range []string{"memory", "sqlite"} {
+		files := &config.BotConfiguration{
+			JMdictFile:      filepath.Join(dir, "JMdict_"+dictionaryStore),
+			DictionaryStore: dictionaryStore,
+		}
+		before := loadTestDictionaries(t, files, db, testJMdict)
+		after := loadTestDictionaries(t, files, db, updated)
+
+		diff, err := compareDictionaries(before.dictionary, after.dictionary)
+		if err != nil {
+			t.Fatalf("%s: %s", dictionaryStore, err.Error())
+		}
+		if len(diff.Modified) != 1 || strings.Join(diff.Modified[0].RemovedGlosses, ";") != "cat" ||
+			strings.Join(diff.Modified[0].AddedGlosses, ";") != "cat (animal)" {
+			t.Errorf("%s: expected the gloss of 猫 to change, got %+v", dictionaryStore, diff.Modified)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/database/models"
+	"github.com/hakasec/japanbot-go/bot/furigana"
+	"github.com/hakasec/japanbot-go/bot/helpers"
+	"github.com/hakasec/japanbot-go/bot/kana"
+)
+
+// romajiOff is the romaji style which hides romaji in definitions
+const romajiOff = "off"
+
+func (b *JapanBot) romaji(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if helpers.StringSliceContains(commandKeywords(args[0]), "style") {
+		b.setRomajiStyle(args, s, m)
+		return
+	}
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a sentence!")
+		return
+	}
+
+	// romanise kanji by their most likely reading
+	opts, _ := b.romajiOptions(m)
+	parts := furigana.Annotate(b.dictionary(), strings.Join(args[1:], " "))
+	s.ChannelMessageSend(m.ChannelID, kana.ToRomaji(furigana.Reading(parts), opts))
+}
+
+// setRomajiStyle handles jpn!romaji!style <style> [macrons|doubled],
+// or jpn!romaji!style!channel to set the default for the channel
+func (b *JapanBot) setRomajiStyle(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if len(args) < 2 || len(args) > 3 {
+		s.ChannelMessageSend(
+			m.ChannelID,
+			"Use jpn!romaji!style [hepburn|kunrei|nihon|off] [macrons|doubled]",
+		)
+		return
+	}
+
+	style := strings.ToLower(args[1])
+	if style != romajiOff {
+		if _, err := kana.ParseStyle(style); err != nil {
+			s.ChannelMessageSend(m.ChannelID, "That isn't a valid romaji style!")
+			return
+		}
+	}
+	doubled := 0
+	if len(args) == 3 {
+		switch strings.ToLower(args[2]) {
+		case "macrons":
+		case "doubled":
+			doubled = 1
+		default:
+			s.ChannelMessageSend(m.ChannelID, "Long vowels can only be macrons or doubled!")
+			return
+		}
+	}
+
+	ownerID := m.Author.ID
+	if helpers.StringSliceContains(commandKeywords(args[0]), "channel") {
+		ownerID = m.ChannelID
+	}
+	if err := b.changeRomajiStyle(ownerID, style, doubled); err != nil {
+		s.ChannelMessageSend(
+			m.ChannelID,
+			fmt.Sprintf("That failed: %s", err.Error()),
+		)
+	} else {
+		s.ChannelMessageSend(m.ChannelID, "Done :)")
+	}
+}
+
+func (b *JapanBot) changeRomajiStyle(ownerID, style string, doubled int) error {
+	p := &models.Preference{}
+	err := b.preferences.Get(
+		map[string]interface{}{
+			"OwnerID": ownerID,
+		},
+		p,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			p.OwnerID = ownerID
+			p.RomajiStyle = style
+			p.DoubledVowels = doubled
+			return b.preferences.Add(p)
+		}
+		return err
+	}
+
+	p.RomajiStyle = style
+	p.DoubledVowels = doubled
+	return b.preferences.Update(p)
+}
+
+// romajiOptions finds the romaji style for the author of a message,
+// falling back to the style of the channel and then Hepburn with macrons.
+// It also reports whether romaji should be shown in definitions
+func (b *JapanBot) romajiOptions(m *discordgo.Message) (kana.RomajiOptions, bool) {
+	for _, ownerID := range []string{m.Author.ID, m.ChannelID} {
+		var p models.Preference
+		err := b.preferences.Get(
+			map[string]interface{}{
+				"OwnerID": ownerID,
+			},
+			&p,
+		)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				fmt.Printf("Error getting preferences: %s\n", err.Error())
+			}
+			continue
+		}
+		if p.RomajiStyle == "" {
+			continue
+		}
+
+		style, _ := kana.ParseStyle(p.RomajiStyle)
+		opts := kana.RomajiOptions{Style: style, Doubled: p.DoubledVowels != 0}
+		return opts, p.RomajiStyle != romajiOff
+	}
+	return kana.RomajiOptions{}, true
+}
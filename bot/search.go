@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/helpers"
+)
+
+// searchPageSize is the number of results on each page of jpn!search
+const searchPageSize = 20
+
+func (b *JapanBot) search(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if len(args) < 2 || len(args) > 3 {
+		s.ChannelMessageSend(
+			m.ChannelID,
+			"Usage: jpn!search <pattern> [page], e.g. jpn!search 食* or jpn!search [reading]たべ*",
+		)
+		return
+	}
+
+	pattern := args[1]
+	page := 1
+	if len(args) == 3 {
+		var err error
+		if page, err = strconv.Atoi(args[2]); err != nil || page < 1 {
+			s.ChannelMessageSend(m.ChannelID, "Page number is invalid!")
+			return
+		}
+	}
+
+	d := b.dictionary()
+	result := d.Search(pattern, (page-1)*searchPageSize, searchPageSize)
+	if len(result.Keys) == 0 {
+		if result.Truncated {
+			s.ChannelMessageSend(m.ChannelID, "That search took too long, try a more specific pattern!")
+		} else {
+			s.ChannelMessageSend(m.ChannelID, "No matches found :(")
+		}
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("```\nMatches for %s (page %d):\n", pattern, page))
+	width := helpers.GetNumDigits(len(result.Keys))
+	for i, key := range result.Keys {
+		message.WriteString(
+			fmt.Sprintf(
+				"%d: %s%s\n",
+				i+1,
+				strings.Repeat(" ", width-helpers.GetNumDigits(i+1)),
+				keyLabel(d, key),
+			),
+		)
+	}
+	message.WriteString(
+		fmt.Sprintf("\nUse jpn!analyse [1-%d] for the full definition\n", len(result.Keys)),
+	)
+	if result.More {
+		message.WriteString(fmt.Sprintf("Use jpn!search %s %d for more\n", pattern, page+1))
+	} else if result.Truncated {
+		message.WriteString("The search was cut short, try a more specific pattern for more\n")
+	}
+	message.WriteString("```")
+
+	b.setAnalyseRequest(m.ChannelID, result.Keys)
+	s.ChannelMessageSend(m.ChannelID, message.String())
+}
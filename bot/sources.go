@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hakasec/japanbot-go/bot/config"
+	"github.com/hakasec/japanbot-go/bot/database"
+	"github.com/hakasec/japanbot-go/bot/database/store"
+	"github.com/hakasec/japanbot-go/bot/datafile"
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+	"github.com/hakasec/japanbot-go/bot/yomichan"
+)
+
+// loadDictionaries loads JMdict into the configured store, followed by
+// the other sources
+func loadDictionaries(files *config.BotConfiguration, db *database.DBConnection) (*dictionaries, error) {
+	var (
+		d   dictionary.Words
+		err error
+	)
+	switch files.DictionaryStore {
+	case "", "memory":
+		d, err = dictionary.Open(files.JMdictFile, loadProgress("JMdict"))
+	case "sqlite":
+		d, err = loadStore(db, files.JMdictFile)
+	default:
+		err = fmt.Errorf("unknown dictionary store %s", files.DictionaryStore)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := loadSources(d, files)
+	if err != nil {
+		return nil, err
+	}
+	return &dictionaries{dictionary: d, sources: sources}, nil
+}
+
+// loadSources creates the sources words are looked up in, starting with
+// JMdict followed by JMnedict and any sources listed in the config
+func loadSources(jmdict dictionary.Source, botConfig *config.BotConfiguration) (*dictionary.Sources, error) {
+	sources := dictionary.NewSources(jmdict)
+	if botConfig.JMnedictFile != "" {
+		names, err := loadNames(botConfig.JMnedictFile)
+		if err != nil {
+			return nil, err
+		}
+		sources.Add(names)
+	}
+
+	for _, sourceConfig := range botConfig.Sources {
+		source, err := loadSource(sourceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %s", sourceConfig.Name, err.Error())
+		}
+		sources.Add(source)
+	}
+	return sources, nil
+}
+
+func loadSource(sourceConfig config.SourceConfiguration) (dictionary.Source, error) {
+	switch sourceConfig.Type {
+	case "jmnedict":
+		return loadNames(sourceConfig.File)
+	case "jmdict":
+		d, err := dictionary.Open(sourceConfig.File, loadProgress(sourceConfig.Name))
+		if err != nil {
+			return nil, err
+		}
+		return d.WithInfo(sourceInfo(sourceConfig.Name, sourceConfig)), nil
+	case "yomichan":
+		return loadYomichan(sourceConfig)
+	}
+
+	r, err := datafile.Open(sourceConfig.File)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	info := sourceInfo(sourceConfig.Name, sourceConfig)
+	switch sourceConfig.Type {
+	case "glossary":
+		j, err := dictionary.LoadGlossary(r)
+		if err != nil {
+			return nil, err
+		}
+		return dictionary.New(j).WithInfo(info), nil
+	}
+	return nil, fmt.Errorf("unknown source type %s", sourceConfig.Type)
+}
+
+// loadYomichan loads a Yomichan dictionary archive, named by its title
+// unless the config gives a name
+func loadYomichan(sourceConfig config.SourceConfiguration) (dictionary.Source, error) {
+	y, err := yomichan.Open(sourceConfig.File)
+	if err != nil {
+		return nil, err
+	}
+
+	name := sourceConfig.Name
+	if name == "" {
+		name = y.Index.Title
+	}
+	return dictionary.New(y.ToJMdict()).WithInfo(sourceInfo(name, sourceConfig)), nil
+}
+
+// findDictionaryFiles returns the config with the JMdict, JMnedict and
+// KANJIDIC2 files found in jmdict_file if it is a directory, keeping any
+// of the files which are set in the config
+func findDictionaryFiles(botConfig *config.BotConfiguration) (*config.BotConfiguration, error) {
+	info, err := os.Stat(botConfig.JMdictFile)
+	if err != nil || !info.IsDir() {
+		// errors are reported when the file is opened
+		return botConfig, nil
+	}
+
+	files, err := datafile.Scan(botConfig.JMdictFile)
+	if err != nil {
+		return nil, err
+	}
+	jmdictFile, ok := files[datafile.JMdict]
+	if !ok {
+		return nil, fmt.Errorf("%s doesn't contain a JMdict file", botConfig.JMdictFile)
+	}
+
+	found := *botConfig
+	found.JMdictFile = jmdictFile
+	if found.JMnedictFile == "" {
+		found.JMnedictFile = files[datafile.JMnedict]
+	}
+	if found.KanjidicFile == "" {
+		found.KanjidicFile = files[datafile.Kanjidic2]
+	}
+	return &found, nil
+}
+
+// loadStore imports JMdict into the database if the file has changed
+func loadStore(db *database.DBConnection, file string) (*store.Store, error) {
+	s := store.New(db)
+	if err := s.CreateTables(); err != nil {
+		return nil, err
+	}
+	if err := s.Import(file, loadProgress("JMdict")); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadProgress reports every tenth of a dictionary file read
+func loadProgress(name string) dictionary.Progress {
+	return func(percent int) {
+		if percent%10 == 0 {
+			fmt.Printf("Loading %s: %d%%\n", name, percent)
+		}
+	}
+}
+
+func sourceInfo(name string, sourceConfig config.SourceConfiguration) dictionary.SourceInfo {
+	return dictionary.SourceInfo{
+		Name:        name,
+		Description: "from " + name,
+		Mark:        fmt.Sprintf("[%s] ", name),
+		Fallback:    sourceConfig.Fallback,
+	}
+}
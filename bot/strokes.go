@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"os"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hakasec/japanbot-go/bot/helpers"
+	"github.com/hakasec/japanbot-go/bot/strokes"
+)
+
+const (
+	// strokeFrameSize is the size in pixels of each frame of jpn!strokes
+	strokeFrameSize = 109
+	// strokeFrameColumns is the number of frames in each row of jpn!strokes
+	strokeFrameColumns = 6
+	// strokeDiagramSize is the size in pixels of jpn!strokes!diagram
+	strokeDiagramSize = 327
+)
+
+func (b *JapanBot) strokes(args []string, s *discordgo.Session, m *discordgo.Message) {
+	if b.configuration.KanjiVGDir == "" {
+		s.ChannelMessageSend(m.ChannelID, "Stroke order isn't available, sorry!")
+		return
+	}
+	if len(args) < 2 || args[1] == "" {
+		s.ChannelMessageSend(m.ChannelID, "You haven't entered a character!")
+		return
+	}
+
+	r, _ := utf8.DecodeRuneInString(args[1])
+	character := string(r)
+	c, err := strokes.LoadFile(b.configuration.KanjiVGDir, character)
+	if os.IsNotExist(err) {
+		s.ChannelMessageSend(
+			m.ChannelID,
+			fmt.Sprintf("I don't know the stroke order of %s!", character),
+		)
+		return
+	} else if err != nil {
+		s.ChannelMessageSend(
+			m.ChannelID,
+			fmt.Sprintf("That failed: %s", err.Error()),
+		)
+		return
+	}
+
+	var buf bytes.Buffer
+	if helpers.StringSliceContains(commandKeywords(args[0]), "diagram") {
+		err = png.Encode(&buf, c.Diagram(strokeDiagramSize))
+	} else {
+		err = png.Encode(&buf, c.Frames(strokeFrameSize, strokeFrameColumns))
+	}
+	if err != nil {
+		s.ChannelMessageSend(
+			m.ChannelID,
+			fmt.Sprintf("That failed: %s", err.Error()),
+		)
+		return
+	}
+	s.ChannelFileSend(m.ChannelID, fmt.Sprintf("%x.png", r), &buf)
+}
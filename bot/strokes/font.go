@@ -0,0 +1,58 @@
+package strokes
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// digitGlyphs is a 3x5 bitmap font for the digits 0 to 9
+var digitGlyphs = [10][5]string{
+	{"###", "#.#", "#.#", "#.#", "###"},
+	{".#.", "##.", ".#.", ".#.", "###"},
+	{"###", "..#", "###", "#..", "###"},
+	{"###", "..#", "###", "..#", "###"},
+	{"#.#", "#.#", "###", "..#", "..#"},
+	{"###", "#..", "###", "..#", "###"},
+	{"###", "#..", "###", "#.#", "###"},
+	{"###", "..#", "..#", "..#", "..#"},
+	{"###", "#.#", "###", "#.#", "###"},
+	{"###", "#.#", "###", "..#", "###"},
+}
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// drawNumber draws n with its bottom left corner at (x, y),
+// scaling each pixel of the font to a square of scale pixels
+func drawNumber(dst draw.Image, n int, x, y, scale int, c color.Color) {
+	var digits []int
+	for {
+		digits = append([]int{n % 10}, digits...)
+		n /= 10
+		if n == 0 {
+			break
+		}
+	}
+
+	top := y - glyphHeight*scale
+	for i, digit := range digits {
+		left := x + i*(glyphWidth+1)*scale
+		for row, line := range digitGlyphs[digit] {
+			for column, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				square := image.Rect(
+					left+column*scale,
+					top+row*scale,
+					left+(column+1)*scale,
+					top+(row+1)*scale,
+				)
+				draw.Draw(dst, square, image.NewUniform(c), image.Point{}, draw.Src)
+			}
+		}
+	}
+}
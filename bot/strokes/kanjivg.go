@@ -0,0 +1,127 @@
+// Package strokes renders stroke order diagrams from KanjiVG data
+package strokes
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Character is the strokes of a character in stroke order
+type Character struct {
+	Strokes []Stroke
+}
+
+// Stroke is a single stroke, flattened into a polyline
+type Stroke struct {
+	Points []Point
+	// Label is where the stroke's number is drawn, at its baseline
+	Label Point
+}
+
+// Filename returns the name of the KanjiVG file for a character,
+// which is its code point in hex
+func Filename(character string) string {
+	r, _ := utf8.DecodeRuneInString(character)
+	return fmt.Sprintf("%05x.svg", r)
+}
+
+// LoadFile loads a character from a KanjiVG directory
+func LoadFile(dir, character string) (*Character, error) {
+	f, err := os.Open(filepath.Join(dir, Filename(character)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse reads the strokes and stroke numbers of a KanjiVG SVG file
+func Parse(r io.Reader) (*Character, error) {
+	var (
+		c      = &Character{}
+		labels = make(map[int]Point)
+		dec    = xml.NewDecoder(r)
+	)
+	// KanjiVG files declare entities in an internal DTD subset
+	dec.Strict = false
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "path":
+			points, err := ParsePath(attr(start, "d"))
+			if err != nil {
+				return nil, fmt.Errorf("stroke %d: %s", len(c.Strokes)+1, err.Error())
+			}
+			if len(points) > 0 {
+				c.Strokes = append(c.Strokes, Stroke{Points: points, Label: points[0]})
+			}
+		case "text":
+			var text string
+			if err := dec.DecodeElement(&text, &start); err != nil {
+				return nil, err
+			}
+			number, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil {
+				continue
+			}
+			if position, ok := parseTranslate(attr(start, "transform")); ok {
+				labels[number] = position
+			}
+		}
+	}
+
+	if len(c.Strokes) == 0 {
+		return nil, fmt.Errorf("no strokes found")
+	}
+	for i := range c.Strokes {
+		if label, ok := labels[i+1]; ok {
+			c.Strokes[i].Label = label
+		}
+	}
+	return c, nil
+}
+
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// parseTranslate reads the position from a transform such as matrix(1 0 0 1 8.5 31.63)
+func parseTranslate(transform string) (Point, bool) {
+	if !strings.HasPrefix(transform, "matrix(") {
+		return Point{}, false
+	}
+	fields := strings.Fields(
+		strings.Replace(strings.Trim(transform[len("matrix"):], "()"), ",", " ", -1),
+	)
+	if len(fields) != 6 {
+		return Point{}, false
+	}
+	x, errX := strconv.ParseFloat(fields[4], 64)
+	y, errY := strconv.ParseFloat(fields[5], 64)
+	if errX != nil || errY != nil {
+		return Point{}, false
+	}
+	return Point{x, y}, true
+}
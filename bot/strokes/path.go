@@ -0,0 +1,198 @@
+package strokes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// curveSegments is the number of lines each bezier curve is flattened to
+const curveSegments = 16
+
+// Point is a position in the 109x109 KanjiVG coordinate space
+type Point struct {
+	X, Y float64
+}
+
+// pathTokens matches the commands and numbers of SVG path data
+var pathTokens = regexp.MustCompile(`[MmLlHhVvCcSsQqTtZz]|[-+]?(?:\d*\.\d+|\d+\.?)(?:[eE][-+]?\d+)?`)
+
+// ParsePath parses SVG path data, flattening curves into a polyline
+func ParsePath(d string) ([]Point, error) {
+	var (
+		tokens  = pathTokens.FindAllString(d, -1)
+		points  []Point
+		current Point
+		start   Point
+		// control is the last control point, reflected by S and T commands
+		control Point
+		command byte
+		i       = 0
+	)
+
+	numbers := func(n int) ([]float64, error) {
+		if i+n > len(tokens) {
+			return nil, fmt.Errorf("%c needs %d numbers", command, n)
+		}
+		values := make([]float64, n)
+		for j := range values {
+			value, err := strconv.ParseFloat(tokens[i+j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%c: %s", command, err.Error())
+			}
+			values[j] = value
+		}
+		i += n
+		return values, nil
+	}
+	// offset makes a point absolute for lowercase commands
+	offset := func(x, y float64) Point {
+		if command >= 'a' {
+			return Point{current.X + x, current.Y + y}
+		}
+		return Point{x, y}
+	}
+
+	for i < len(tokens) {
+		if isCommand(tokens[i]) {
+			command = tokens[i][0]
+			i++
+		} else if command == 0 {
+			return nil, fmt.Errorf("path data starts with %s", tokens[i])
+		}
+
+		previous := command
+		switch command {
+		case 'M', 'm':
+			v, err := numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			current = offset(v[0], v[1])
+			start = current
+			points = append(points, current)
+			// further coordinates are lines
+			if command == 'M' {
+				command = 'L'
+			} else {
+				command = 'l'
+			}
+		case 'L', 'l':
+			v, err := numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			current = offset(v[0], v[1])
+			points = append(points, current)
+		case 'H', 'h':
+			v, err := numbers(1)
+			if err != nil {
+				return nil, err
+			}
+			current.X = offset(v[0], 0).X
+			points = append(points, current)
+		case 'V', 'v':
+			v, err := numbers(1)
+			if err != nil {
+				return nil, err
+			}
+			current.Y = offset(0, v[0]).Y
+			points = append(points, current)
+		case 'C', 'c':
+			v, err := numbers(6)
+			if err != nil {
+				return nil, err
+			}
+			c1, c2, end := offset(v[0], v[1]), offset(v[2], v[3]), offset(v[4], v[5])
+			points = append(points, cubic(current, c1, c2, end)...)
+			current, control = end, c2
+		case 'S', 's':
+			v, err := numbers(4)
+			if err != nil {
+				return nil, err
+			}
+			c1 := current
+			if isCubic(previous) {
+				c1 = mirror(control, current)
+			}
+			c2, end := offset(v[0], v[1]), offset(v[2], v[3])
+			points = append(points, cubic(current, c1, c2, end)...)
+			current, control = end, c2
+		case 'Q', 'q':
+			v, err := numbers(4)
+			if err != nil {
+				return nil, err
+			}
+			c, end := offset(v[0], v[1]), offset(v[2], v[3])
+			points = append(points, quadratic(current, c, end)...)
+			current, control = end, c
+		case 'T', 't':
+			v, err := numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			c := current
+			if isQuadratic(previous) {
+				c = mirror(control, current)
+			}
+			end := offset(v[0], v[1])
+			points = append(points, quadratic(current, c, end)...)
+			current, control = end, c
+		case 'Z', 'z':
+			if i < len(tokens) && !isCommand(tokens[i]) {
+				return nil, fmt.Errorf("%c takes no numbers", command)
+			}
+			current = start
+			points = append(points, current)
+		default:
+			return nil, fmt.Errorf("unknown path command %c", command)
+		}
+	}
+	return points, nil
+}
+
+func isCommand(token string) bool {
+	c := token[0]
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isCubic(command byte) bool {
+	return command == 'C' || command == 'c' || command == 'S' || command == 's'
+}
+
+func isQuadratic(command byte) bool {
+	return command == 'Q' || command == 'q' || command == 'T' || command == 't'
+}
+
+// mirror reflects a control point about the current point
+func mirror(control, current Point) Point {
+	return Point{2*current.X - control.X, 2*current.Y - control.Y}
+}
+
+// cubic flattens a cubic bezier, excluding its start point
+func cubic(p0, p1, p2, p3 Point) []Point {
+	points := make([]Point, curveSegments)
+	for i := range points {
+		t := float64(i+1) / curveSegments
+		u := 1 - t
+		points[i] = Point{
+			u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+			u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+		}
+	}
+	return points
+}
+
+// quadratic flattens a quadratic bezier, excluding its start point
+func quadratic(p0, p1, p2 Point) []Point {
+	points := make([]Point, curveSegments)
+	for i := range points {
+		t := float64(i+1) / curveSegments
+		u := 1 - t
+		points[i] = Point{
+			u*u*p0.X + 2*u*t*p1.X + t*t*p2.X,
+			u*u*p0.Y + 2*u*t*p1.Y + t*t*p2.Y,
+		}
+	}
+	return points
+}
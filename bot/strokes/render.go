@@ -0,0 +1,190 @@
+package strokes
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+const (
+	// viewBoxSize is the width and height of the KanjiVG coordinate space
+	viewBoxSize = 109
+	// strokeWidth is the width of a stroke in KanjiVG units
+	strokeWidth = 3.5
+)
+
+var (
+	background   = color.RGBA{255, 255, 255, 255}
+	guideColor   = color.RGBA{220, 220, 220, 255}
+	doneColor    = color.RGBA{170, 170, 170, 255}
+	currentColor = color.RGBA{0, 0, 0, 255}
+	startColor   = color.RGBA{220, 40, 40, 255}
+)
+
+// strokeColors are cycled through to tell strokes apart in a diagram
+var strokeColors = []color.RGBA{
+	{191, 0, 0, 255},
+	{191, 96, 0, 255},
+	{140, 140, 0, 255},
+	{0, 140, 0, 255},
+	{0, 140, 140, 255},
+	{0, 0, 191, 255},
+	{96, 0, 191, 255},
+}
+
+// Diagram renders the whole character at size x size pixels,
+// each stroke in its own colour and labelled with its number
+func (c *Character) Diagram(size int) *image.RGBA {
+	img := newCanvas(size, size)
+	bounds := img.Bounds()
+	drawGuides(img, bounds)
+
+	scale := float64(size) / viewBoxSize
+	for i, stroke := range c.Strokes {
+		strokeColor := strokeColors[i%len(strokeColors)]
+		drawStroke(img, bounds, stroke, scale, strokeColor)
+	}
+	// numbers go on top of every stroke
+	fontScale := fontScaleFor(size)
+	for i, stroke := range c.Strokes {
+		strokeColor := strokeColors[i%len(strokeColors)]
+		drawNumber(
+			img,
+			i+1,
+			int(stroke.Label.X*scale),
+			int(stroke.Label.Y*scale),
+			fontScale,
+			strokeColor,
+		)
+	}
+	return img
+}
+
+// Frames renders one frame per stroke in a grid with the given number of
+// columns. Each frame shows the strokes before it in grey, the new stroke in
+// black with a dot where it starts, and the stroke number in the corner
+func (c *Character) Frames(cellSize, columns int) *image.RGBA {
+	if columns > len(c.Strokes) {
+		columns = len(c.Strokes)
+	}
+	rows := (len(c.Strokes) + columns - 1) / columns
+	img := newCanvas(cellSize*columns, cellSize*rows)
+
+	scale := float64(cellSize) / viewBoxSize
+	fontScale := fontScaleFor(cellSize)
+	for i := range c.Strokes {
+		cell := image.Rect(0, 0, cellSize, cellSize).Add(
+			image.Pt((i%columns)*cellSize, (i/columns)*cellSize),
+		)
+		drawGuides(img, cell)
+		for j, stroke := range c.Strokes[:i+1] {
+			strokeColor := doneColor
+			if j == i {
+				strokeColor = currentColor
+			}
+			drawStroke(img, cell, stroke, scale, strokeColor)
+		}
+
+		start := c.Strokes[i].Points[0]
+		fillDisc(img, cell, start.X*scale, start.Y*scale, strokeWidth*scale, startColor)
+		drawNumber(
+			img,
+			i+1,
+			cell.Min.X+fontScale,
+			cell.Min.Y+(glyphHeight+1)*fontScale,
+			fontScale,
+			currentColor,
+		)
+	}
+	return img
+}
+
+func newCanvas(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	return img
+}
+
+// fontScaleFor picks a digit size suited to an image size
+func fontScaleFor(size int) int {
+	if scale := size / 60; scale > 1 {
+		return scale
+	}
+	return 1
+}
+
+// drawGuides draws a border and centre cross inside a cell
+func drawGuides(img *image.RGBA, cell image.Rectangle) {
+	midX := (cell.Min.X + cell.Max.X) / 2
+	midY := (cell.Min.Y + cell.Max.Y) / 2
+	for x := cell.Min.X; x < cell.Max.X; x++ {
+		img.SetRGBA(x, cell.Min.Y, guideColor)
+		img.SetRGBA(x, cell.Max.Y-1, guideColor)
+		if x%4 < 2 {
+			img.SetRGBA(x, midY, guideColor)
+		}
+	}
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		img.SetRGBA(cell.Min.X, y, guideColor)
+		img.SetRGBA(cell.Max.X-1, y, guideColor)
+		if y%4 < 2 {
+			img.SetRGBA(midX, y, guideColor)
+		}
+	}
+}
+
+// drawStroke draws a stroke into a cell with round caps and joins.
+// The stroke is built up in a mask first so overlapping parts of the same
+// stroke don't darken its anti-aliased edges
+func drawStroke(img *image.RGBA, cell image.Rectangle, stroke Stroke, scale float64, c color.RGBA) {
+	mask := image.NewAlpha(image.Rect(0, 0, cell.Dx(), cell.Dy()))
+	radius := strokeWidth * scale / 2
+	// stamp a disc every half pixel along the stroke
+	step := 0.5 / scale
+	points := stroke.Points
+	stampDisc(mask, points[0].X*scale, points[0].Y*scale, radius)
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		length := math.Hypot(to.X-from.X, to.Y-from.Y)
+		steps := int(math.Ceil(length / step))
+		for s := 1; s <= steps; s++ {
+			t := float64(s) / float64(steps)
+			stampDisc(
+				mask,
+				(from.X+(to.X-from.X)*t)*scale,
+				(from.Y+(to.Y-from.Y)*t)*scale,
+				radius,
+			)
+		}
+	}
+	draw.DrawMask(img, cell, image.NewUniform(c), image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// stampDisc adds an anti-aliased disc to a mask, keeping the highest coverage
+func stampDisc(mask *image.Alpha, cx, cy, radius float64) {
+	bounds := mask.Bounds()
+	for y := int(cy - radius - 1); y <= int(cy+radius+1); y++ {
+		for x := int(cx - radius - 1); x <= int(cx+radius+1); x++ {
+			if !(image.Point{x, y}).In(bounds) {
+				continue
+			}
+			distance := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			coverage := math.Min(1, math.Max(0, radius+0.5-distance))
+			if coverage == 0 {
+				continue
+			}
+			alpha := uint8(coverage * 255)
+			if alpha > mask.AlphaAt(x, y).A {
+				mask.SetAlpha(x, y, color.Alpha{alpha})
+			}
+		}
+	}
+}
+
+// fillDisc draws a solid disc centred at (cx, cy) relative to a cell
+func fillDisc(img *image.RGBA, cell image.Rectangle, cx, cy, radius float64, c color.RGBA) {
+	mask := image.NewAlpha(image.Rect(0, 0, cell.Dx(), cell.Dy()))
+	stampDisc(mask, cx, cy, radius)
+	draw.DrawMask(img, cell, image.NewUniform(c), image.Point{}, mask, image.Point{}, draw.Over)
+}
@@ -0,0 +1,134 @@
+package strokes
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+)
+
+// testSVG is a trimmed down KanjiVG file for 二
+const testSVG = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.0//EN" "http://www.w3.org/TR/2001/REC-SVG-20010904/DTD/svg10.dtd" [
+<!ATTLIST g
+xmlns:kvg CDATA #FIXED "http://kanjivg.tagaini.net"
+kvg:element CDATA #IMPLIED >
+]>
+<svg xmlns="http://www.w3.org/2000/svg" width="109" height="109" viewBox="0 0 109 109">
+<g id="kvg:StrokePaths_04e8c" style="fill:none;stroke:#000000;stroke-width:3;">
+<g id="kvg:04e8c" kvg:element="二">
+	<path id="kvg:04e8c-s1" kvg:type="㇐" d="M26.5,30.5c2.5,0.5,5,0.5,8,0.25c10-1,30-3,45-3.25"/>
+	<path id="kvg:04e8c-s2" kvg:type="㇐" d="M13.25,78.5C17,79,20,79,24,78.75L97,74.5"/>
+</g>
+</g>
+<g id="kvg:StrokeNumbers_04e8c" style="font-size:8;fill:#808080">
+	<text transform="matrix(1 0 0 1 18.50 32.63)">1</text>
+	<text transform="matrix(1 0 0 1 5.50 80.63)">2</text>
+</g>
+</svg>`
+
+func TestParsePath(t *testing.T) {
+	points, err := ParsePath("M10,10 l5-5h5V20z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Point{{10, 10}, {15, 5}, {20, 5}, {20, 20}, {10, 10}}
+	if len(points) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, points)
+	}
+	for i, p := range points {
+		if p != expected[i] {
+			t.Errorf("point %d: expected %v, got %v", i, expected[i], p)
+		}
+	}
+}
+
+func TestParsePathCurve(t *testing.T) {
+	points, err := ParsePath("M0,0c0,10,10,10,10,0s10-10,10,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 1+2*curveSegments {
+		t.Fatalf("expected %d points, got %d", 1+2*curveSegments, len(points))
+	}
+	end := points[len(points)-1]
+	if math.Abs(end.X-20) > 1e-9 || math.Abs(end.Y) > 1e-9 {
+		t.Errorf("expected the curve to end at (20, 0), got %v", end)
+	}
+	// the reflected control point pulls the second curve upwards
+	if middle := points[curveSegments+curveSegments/2]; middle.Y >= 0 {
+		t.Errorf("expected the second curve to go above 0, got %v", middle)
+	}
+
+	if _, err := ParsePath("M0"); err == nil {
+		t.Error("expected an error for missing coordinates")
+	}
+}
+
+func TestParsePathMalformed(t *testing.T) {
+	// H and V before any point start from the origin
+	points, err := ParsePath("H5V3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0] != (Point{5, 0}) || points[1] != (Point{5, 3}) {
+		t.Errorf("expected (5, 0) and (5, 3), got %v", points)
+	}
+
+	if _, err := ParsePath("M0,0Z5"); err == nil {
+		t.Error("expected an error for numbers after Z")
+	}
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse(strings.NewReader(testSVG))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Strokes) != 2 {
+		t.Fatalf("expected 2 strokes, got %d", len(c.Strokes))
+	}
+	if label := c.Strokes[1].Label; label != (Point{5.5, 80.63}) {
+		t.Errorf("expected label at (5.5, 80.63), got %v", label)
+	}
+	if Filename("二") != "04e8c.svg" {
+		t.Errorf("expected 04e8c.svg, got %s", Filename("二"))
+	}
+}
+
+func TestFrames(t *testing.T) {
+	c, err := Parse(strings.NewReader(testSVG))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := c.Frames(109, 5)
+	if bounds := img.Bounds(); bounds.Dx() != 218 || bounds.Dy() != 109 {
+		t.Fatalf("expected a 218x109 grid, got %v", bounds)
+	}
+	// the second stroke is only drawn in the second frame
+	if img.RGBAAt(60, 76) != background {
+		t.Errorf("expected the first frame not to show stroke 2, got %v", img.RGBAAt(60, 76))
+	}
+	if img.RGBAAt(109+60, 76) != currentColor {
+		t.Errorf("expected the second frame to show stroke 2, got %v", img.RGBAAt(109+60, 76))
+	}
+	if img.RGBAAt(109+60, 29) != doneColor {
+		t.Errorf("expected the second frame to show stroke 1 in grey, got %v", img.RGBAAt(109+60, 29))
+	}
+}
+
+func TestDiagram(t *testing.T) {
+	c, err := Parse(strings.NewReader(testSVG))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := c.Diagram(218)
+	if got := img.RGBAAt(120, 152); got != strokeColors[1] {
+		t.Errorf("expected stroke 2 in its colour, got %v", got)
+	}
+	if got := img.At(0, 0).(color.RGBA); got != guideColor {
+		t.Errorf("expected a guide border, got %v", got)
+	}
+}
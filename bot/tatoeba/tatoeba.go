@@ -0,0 +1,231 @@
+// Package tatoeba loads Japanese example sentences and their English
+// translations from the Tatoeba corpus, indexed by the words they contain
+package tatoeba
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hakasec/japanbot-go/bot/cache"
+)
+
+// Example is a Japanese sentence and its translation
+type Example struct {
+	ID       int
+	Japanese string
+	English  string
+}
+
+// Corpus holds example sentences by the headwords of their words
+type Corpus struct {
+	Examples []Example
+	// Words holds the positions in Examples of the sentences using each
+	// headword, with good examples and then shorter sentences first
+	Words map[string][]int
+}
+
+// pair is a Japanese sentence and its linked English sentences
+type pair struct {
+	japanese     string
+	translations []translation
+}
+
+type translation struct {
+	id   int
+	text string
+}
+
+// Load loads a corpus from a tab separated file of sentence pairs, with
+// the Japanese sentence's ID and text followed by the English sentence's
+// ID and text, and a Jpn_Indices file of Japanese sentence ID, English
+// sentence ID and the words of the sentence. Only sentences which are
+// in both files are kept
+func Load(pairs, indices io.Reader) (*Corpus, error) {
+	sentences, err := loadPairs(pairs)
+	if err != nil {
+		return nil, fmt.Errorf("sentence pairs: %s", err.Error())
+	}
+
+	c := &Corpus{Words: make(map[string][]int)}
+	// good holds the good examples of each headword, which are marked with ~
+	good := make(map[string]map[int]bool)
+	err = eachLine(indices, func(fields []string) error {
+		if len(fields) < 3 {
+			return errors.New("expected three fields")
+		}
+		japaneseID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return err
+		}
+		englishID, _ := strconv.Atoi(fields[1])
+		p, ok := sentences[japaneseID]
+		if !ok {
+			return nil
+		}
+
+		example := Example{
+			ID:       japaneseID,
+			Japanese: p.japanese,
+			English:  p.translations[0].text,
+		}
+		for _, t := range p.translations {
+			if t.id == englishID {
+				example.English = t.text
+			}
+		}
+		position := len(c.Examples)
+		c.Examples = append(c.Examples, example)
+
+		for _, word := range strings.Fields(fields[2]) {
+			headword, isGood := parseWord(word)
+			if headword == "" {
+				continue
+			}
+			words := c.Words[headword]
+			if len(words) > 0 && words[len(words)-1] == position {
+				continue
+			}
+			c.Words[headword] = append(words, position)
+			if isGood {
+				if good[headword] == nil {
+					good[headword] = make(map[int]bool)
+				}
+				good[headword][position] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indices: %s", err.Error())
+	}
+
+	for headword, positions := range c.Words {
+		marked := good[headword]
+		sort.SliceStable(positions, func(i, j int) bool {
+			a, b := positions[i], positions[j]
+			if marked[a] != marked[b] {
+				return marked[a]
+			}
+			return utf8.RuneCountInString(c.Examples[a].Japanese) <
+				utf8.RuneCountInString(c.Examples[b].Japanese)
+		})
+	}
+	return c, nil
+}
+
+// loadPairs reads sentence pairs by the ID of the Japanese sentence
+func loadPairs(r io.Reader) (map[int]*pair, error) {
+	sentences := make(map[int]*pair)
+	err := eachLine(r, func(fields []string) error {
+		if len(fields) < 4 {
+			return errors.New("expected four fields")
+		}
+		japaneseID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return err
+		}
+		englishID, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return err
+		}
+
+		p, ok := sentences[japaneseID]
+		if !ok {
+			p = &pair{japanese: fields[1]}
+			sentences[japaneseID] = p
+		}
+		p.translations = append(p.translations, translation{id: englishID, text: fields[3]})
+		return nil
+	})
+	return sentences, err
+}
+
+// parseWord parses a word of an index line, which is written as
+// headword(reading)[sense]{form in the sentence}, followed by ~
+// if the sentence is a good example of the word
+func parseWord(word string) (string, bool) {
+	headword := word
+	if i := strings.IndexAny(word, "([{~|"); i != -1 {
+		headword = word[:i]
+	}
+	return headword, strings.HasSuffix(word, "~")
+}
+
+// eachLine calls f with the tab separated fields of each line of r
+func eachLine(r io.Reader, f func(fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if err := f(strings.Split(line, "\t")); err != nil {
+			return fmt.Errorf("line %d: %s", lineNumber, err.Error())
+		}
+	}
+	return scanner.Err()
+}
+
+// Lookup finds up to limit examples using a headword,
+// skipping sentences longer than maxLength runes if it is above 0
+func (c *Corpus) Lookup(headword string, limit, maxLength int) []Example {
+	var examples []Example
+	for _, position := range c.Words[headword] {
+		if len(examples) == limit {
+			break
+		}
+		example := c.Examples[position]
+		if maxLength > 0 && utf8.RuneCountInString(example.Japanese) > maxLength {
+			continue
+		}
+		examples = append(examples, example)
+	}
+	return examples
+}
+
+// cacheVersion is changed whenever Corpus changes, to rebuild old caches
+const cacheVersion = 1
+
+// Open loads a corpus from its files, using the cache kept next to the
+// sentence pairs when it was built from the same files, and otherwise
+// building the corpus and writing the cache for the next time
+func Open(pairsFile, indicesFile string) (*Corpus, error) {
+	path := cache.Path(pairsFile)
+	c := &Corpus{}
+	err := cache.Read(path, cacheVersion, c, pairsFile, indicesFile)
+	if err == nil {
+		return c, nil
+	} else if err != cache.ErrStale {
+		fmt.Printf("Rebuilding example cache: %s\n", err.Error())
+	}
+
+	pairs, err := os.Open(pairsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer pairs.Close()
+	indices, err := os.Open(indicesFile)
+	if err != nil {
+		return nil, err
+	}
+	defer indices.Close()
+
+	c, err = Load(pairs, indices)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Write(path, cacheVersion, c, pairsFile, indicesFile); err != nil {
+		fmt.Printf("Error writing example cache: %s\n", err.Error())
+	}
+	return c, nil
+}
@@ -0,0 +1,92 @@
+package tatoeba
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hakasec/japanbot-go/bot/cache"
+)
+
+const (
+	testPairs = "1\t私は学生です。\t10\tI am a student.\n" +
+		"2\t彼は先生で、私は学生です。\t20\tHe is a teacher and I am a student.\n" +
+		"2\t彼は先生で、私は学生です。\t21\tHe's a teacher and I'm a student.\n" +
+		"3\t学生が来た。\t30\tA student came.\n" +
+		"4\t猫がいる。\t40\tThere is a cat.\n"
+	testIndices = "1\t10\t私(わたし)[01] は 学生{学生} です\n" +
+		"2\t21\t彼(かれ)[01] は 先生 で 私(わたし) は 学生~ です\n" +
+		"3\t30\t学生 が 来る(くる){来た}\n" +
+		"5\t50\t犬 が いる\n"
+)
+
+func TestLoad(t *testing.T) {
+	c, err := Load(strings.NewReader(testPairs), strings.NewReader(testIndices))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Examples) != 3 {
+		t.Fatalf("expected 3 examples, got %v", c.Examples)
+	}
+
+	// the good example comes first, then the shortest
+	examples := c.Lookup("学生", 10, 0)
+	var ids []int
+	for _, example := range examples {
+		ids = append(ids, example.ID)
+	}
+	if len(ids) != 3 || ids[0] != 2 || ids[1] != 3 || ids[2] != 1 {
+		t.Errorf("expected 2, 3, 1, got %v", ids)
+	}
+	if examples[0].English != "He's a teacher and I'm a student." {
+		t.Errorf("expected the linked translation, got %s", examples[0].English)
+	}
+
+	if examples := c.Lookup("学生", 2, 8); len(examples) != 2 || examples[0].ID != 3 {
+		t.Errorf("expected short examples 3 and 1, got %v", examples)
+	}
+	if examples := c.Lookup("来る", 10, 0); len(examples) != 1 {
+		t.Errorf("expected an example of 来る, got %v", examples)
+	}
+	if examples := c.Lookup("犬", 10, 0); len(examples) != 0 {
+		t.Errorf("expected sentences without pairs to be skipped, got %v", examples)
+	}
+}
+
+func TestOpenCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tatoeba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pairsFile := filepath.Join(dir, "pairs.tsv")
+	indicesFile := filepath.Join(dir, "jpn_indices.csv")
+	ioutil.WriteFile(pairsFile, []byte(testPairs), 0644)
+	ioutil.WriteFile(indicesFile, []byte(testIndices), 0644)
+
+	if _, err := Open(pairsFile, indicesFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(pairsFile + ".cache"); err != nil {
+		t.Fatalf("expected a cache to be written: %s", err.Error())
+	}
+
+	// a fresh cache is read instead of the files
+	cached := &Corpus{
+		Examples: []Example{{ID: 9, Japanese: "犬だ。", English: "It's a dog."}},
+		Words:    map[string][]int{"犬": {0}},
+	}
+	if err := cache.Write(cache.Path(pairsFile), cacheVersion, cached, pairsFile, indicesFile); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Open(pairsFile, indicesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if examples := c.Lookup("犬", 10, 0); len(examples) != 1 || examples[0].ID != 9 {
+		t.Errorf("expected the cached corpus, got %v", c)
+	}
+}
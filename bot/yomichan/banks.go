@@ -0,0 +1,191 @@
+package yomichan
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// parseTerm parses a term bank row. Format 1 lists the glossary as the
+// remaining items of the row, later formats as an array followed by the
+// sequence number and term tags
+func parseTerm(row []json.RawMessage, format int) (Term, error) {
+	if len(row) < 5 {
+		return Term{}, errors.New("term has too few fields")
+	}
+
+	var (
+		term Term
+		tags string
+		err  error
+	)
+	if err = json.Unmarshal(row[0], &term.Expression); err != nil {
+		return term, err
+	}
+	if err = json.Unmarshal(row[1], &term.Reading); err != nil {
+		return term, err
+	}
+	// tags may be null
+	json.Unmarshal(row[2], &tags)
+	term.DefinitionTags = strings.Fields(tags)
+	tags = ""
+	json.Unmarshal(row[3], &tags)
+	term.Rules = strings.Fields(tags)
+	json.Unmarshal(row[4], &term.Score)
+
+	if format == 1 {
+		for _, item := range row[5:] {
+			if text := glossaryText(item); text != "" {
+				term.Glossary = append(term.Glossary, text)
+			}
+		}
+		return term, nil
+	}
+
+	if len(row) < 8 {
+		return term, errors.New("term has too few fields")
+	}
+	var glossary []json.RawMessage
+	if err = json.Unmarshal(row[5], &glossary); err != nil {
+		return term, err
+	}
+	for _, item := range glossary {
+		if text := glossaryText(item); text != "" {
+			term.Glossary = append(term.Glossary, text)
+		}
+	}
+	json.Unmarshal(row[6], &term.Sequence)
+	tags = ""
+	json.Unmarshal(row[7], &tags)
+	term.TermTags = strings.Fields(tags)
+	return term, nil
+}
+
+// glossaryText flattens a glossary item, which is either a string or
+// structured content, to plain text
+func glossaryText(item json.RawMessage) string {
+	var content interface{}
+	if err := json.Unmarshal(item, &content); err != nil {
+		return ""
+	}
+	var builder strings.Builder
+	flattenContent(content, &builder)
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// blockTags are structured content tags which start on a new line
+var blockTags = map[string]bool{
+	"br": true, "div": true, "li": true, "ol": true, "ul": true, "p": true,
+	"table": true, "tr": true, "details": true, "summary": true,
+}
+
+func flattenContent(content interface{}, builder *strings.Builder) {
+	switch c := content.(type) {
+	case string:
+		builder.WriteString(c)
+	case []interface{}:
+		for _, child := range c {
+			flattenContent(child, builder)
+		}
+	case map[string]interface{}:
+		switch c["type"] {
+		case "text":
+			flattenContent(c["text"], builder)
+			return
+		case "image":
+			return
+		}
+		tag, _ := c["tag"].(string)
+		if tag == "img" || tag == "rt" || tag == "rp" {
+			return
+		}
+		flattenContent(c["content"], builder)
+		if blockTags[tag] {
+			builder.WriteString(" ")
+		}
+	}
+}
+
+// parseTermMeta parses a term meta bank row of expression, mode and data
+func parseTermMeta(row []json.RawMessage) (TermMeta, error) {
+	if len(row) < 3 {
+		return TermMeta{}, errors.New("term meta has too few fields")
+	}
+
+	var meta TermMeta
+	if err := json.Unmarshal(row[0], &meta.Expression); err != nil {
+		return meta, err
+	}
+	if err := json.Unmarshal(row[1], &meta.Mode); err != nil {
+		return meta, err
+	}
+
+	switch meta.Mode {
+	case "freq":
+		var data struct {
+			Reading   string          `json:"reading"`
+			Frequency json.RawMessage `json:"frequency"`
+		}
+		// data is a frequency, or an object holding the reading and frequency
+		if err := json.Unmarshal(row[2], &data); err == nil && data.Frequency != nil {
+			meta.Reading = data.Reading
+			meta.Frequency = frequencyText(data.Frequency)
+		} else {
+			meta.Frequency = frequencyText(row[2])
+		}
+	case "pitch":
+		var data struct {
+			Reading string `json:"reading"`
+			Pitches []struct {
+				Position int `json:"position"`
+			} `json:"pitches"`
+		}
+		if err := json.Unmarshal(row[2], &data); err != nil {
+			return meta, err
+		}
+		meta.Reading = data.Reading
+		for _, pitch := range data.Pitches {
+			meta.Pitches = append(meta.Pitches, pitch.Position)
+		}
+	case "ipa":
+		var data struct {
+			Reading        string `json:"reading"`
+			Transcriptions []struct {
+				IPA string `json:"ipa"`
+			} `json:"transcriptions"`
+		}
+		if err := json.Unmarshal(row[2], &data); err != nil {
+			return meta, err
+		}
+		meta.Reading = data.Reading
+		for _, transcription := range data.Transcriptions {
+			meta.IPA = append(meta.IPA, transcription.IPA)
+		}
+	}
+	return meta, nil
+}
+
+// frequencyText formats a frequency, which is a number, a string or
+// an object with a value and the text to display it as
+func frequencyText(data json.RawMessage) string {
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return text
+	}
+	var value struct {
+		Value        float64 `json:"value"`
+		DisplayValue string  `json:"displayValue"`
+	}
+	if err := json.Unmarshal(data, &value); err == nil {
+		if value.DisplayValue != "" {
+			return value.DisplayValue
+		}
+		return strconv.FormatFloat(value.Value, 'f', -1, 64)
+	}
+	return ""
+}
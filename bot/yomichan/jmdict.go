@@ -0,0 +1,141 @@
+package yomichan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	jmdict "github.com/hakasec/jmdict-go"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+// ToJMdict converts the dictionary to JMdict entries so it can be used as a
+// source. Terms are grouped into entries by their sequence number, or by
+// expression and reading if the dictionary isn't sequenced, with each term
+// as a sense. Metadata is added as senses to the entry it describes, with
+// glosses of the meta type so it isn't searched
+func (d *Dictionary) ToJMdict() *jmdict.JMdict {
+	var (
+		j     = &jmdict.JMdict{}
+		index = make(map[string]int)
+		// byExpression holds the first entry for each expression
+		byExpression = make(map[string]int)
+	)
+	entryFor := func(key, expression, reading string) *jmdict.Entry {
+		if i, ok := index[key]; ok {
+			return &j.Entries[i]
+		}
+		index[key] = len(j.Entries)
+		j.Entries = append(j.Entries, newEntry(len(j.Entries)+1, expression, reading))
+		return &j.Entries[len(j.Entries)-1]
+	}
+
+	for _, term := range d.Terms {
+		key := termKey(term.Expression, term.Reading)
+		if d.Index.Sequenced && term.Sequence != 0 {
+			key = "#" + strconv.Itoa(term.Sequence)
+		}
+		entry := entryFor(key, term.Expression, term.Reading)
+		// sequenced entries can have several spellings
+		addElements(entry, term.Expression, term.Reading)
+
+		sense := jmdict.Sense{
+			POS:  term.Rules,
+			Misc: term.DefinitionTags,
+		}
+		for _, gloss := range term.Glossary {
+			sense.GlossaryItems = append(sense.GlossaryItems, jmdict.Glossary{Definition: gloss})
+		}
+		entry.Senses = append(entry.Senses, sense)
+		// terms found by their expression and reading can also have metadata
+		index[termKey(term.Expression, term.Reading)] = index[key]
+		if _, ok := byExpression[term.Expression]; !ok {
+			byExpression[term.Expression] = index[key]
+		}
+	}
+
+	for _, meta := range d.Meta {
+		gloss := metaText(meta)
+		if gloss == "" {
+			continue
+		}
+		i, found := index[termKey(meta.Expression, meta.Reading)]
+		// metadata without a reading belongs to the first entry for the expression
+		if !found && meta.Reading == "" {
+			i, found = byExpression[meta.Expression]
+		}
+		var entry *jmdict.Entry
+		if found {
+			entry = &j.Entries[i]
+		} else {
+			entry = entryFor(termKey(meta.Expression, meta.Reading), meta.Expression, meta.Reading)
+		}
+		entry.Senses = append(entry.Senses, jmdict.Sense{
+			GlossaryItems: []jmdict.Glossary{{Type: dictionary.MetaGlossType, Definition: gloss}},
+		})
+	}
+	return j
+}
+
+func termKey(expression, reading string) string {
+	if reading == "" || reading == expression {
+		return expression
+	}
+	return expression + "\x00" + reading
+}
+
+func newEntry(id int, expression, reading string) jmdict.Entry {
+	entry := jmdict.Entry{EntryID: "y" + strconv.Itoa(id)}
+	addElements(&entry, expression, reading)
+	return entry
+}
+
+// addElements adds the kanji and reading elements for a term, where a blank
+// reading means the expression is written in kana
+func addElements(entry *jmdict.Entry, expression, reading string) {
+	if reading == "" || reading == expression {
+		addReading(entry, expression)
+		return
+	}
+	for _, k := range entry.KanjiElements {
+		if k.Phrase == expression {
+			addReading(entry, reading)
+			return
+		}
+	}
+	entry.KanjiElements = append(entry.KanjiElements, jmdict.KanjiElement{Phrase: expression})
+	addReading(entry, reading)
+}
+
+func addReading(entry *jmdict.Entry, reading string) {
+	for _, r := range entry.ReadingElements {
+		if r.Phrase == reading {
+			return
+		}
+	}
+	entry.ReadingElements = append(entry.ReadingElements, jmdict.ReadingElement{Phrase: reading})
+}
+
+// metaText describes a term's metadata as a gloss
+func metaText(meta TermMeta) string {
+	switch meta.Mode {
+	case "freq":
+		if meta.Frequency != "" {
+			return "Frequency: " + meta.Frequency
+		}
+	case "pitch":
+		if len(meta.Pitches) > 0 {
+			var positions []string
+			for _, p := range meta.Pitches {
+				positions = append(positions, fmt.Sprintf("[%d]", p))
+			}
+			return fmt.Sprintf("Pitch accent: %s %s", meta.Reading, strings.Join(positions, " "))
+		}
+	case "ipa":
+		if len(meta.IPA) > 0 {
+			return "IPA: " + strings.Join(meta.IPA, ", ")
+		}
+	}
+	return ""
+}
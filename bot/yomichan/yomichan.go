@@ -0,0 +1,173 @@
+// Package yomichan imports dictionaries in the Yomichan zip format,
+// which holds an index.json and banks of terms and term metadata
+package yomichan
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Index describes a dictionary archive
+type Index struct {
+	Title       string `json:"title"`
+	Revision    string `json:"revision"`
+	Format      int    `json:"format"`
+	Version     int    `json:"version"`
+	Sequenced   bool   `json:"sequenced"`
+	Author      string `json:"author"`
+	Description string `json:"description"`
+}
+
+// Term is a row of a term bank
+type Term struct {
+	Expression     string
+	Reading        string
+	DefinitionTags []string
+	// Rules lists the inflection classes of the term, such as v1 or adj-i
+	Rules    []string
+	Score    int
+	Glossary []string
+	Sequence int
+	TermTags []string
+}
+
+// TermMeta is a row of a term meta bank, holding the frequency,
+// pitch accents or IPA transcriptions of a term
+type TermMeta struct {
+	Expression string
+	// Mode is freq, pitch or ipa
+	Mode    string
+	Reading string
+	// Frequency is the frequency as shown by the dictionary
+	Frequency string
+	// Pitches holds the downstep positions of the reading
+	Pitches []int
+	IPA     []string
+}
+
+// Dictionary is the contents of a Yomichan dictionary archive
+type Dictionary struct {
+	Index Index
+	Terms []Term
+	Meta  []TermMeta
+}
+
+// Open reads a Yomichan dictionary archive from a file
+func Open(file string) (*Dictionary, error) {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return read(&r.Reader)
+}
+
+// Read reads a Yomichan dictionary archive
+func Read(r io.ReaderAt, size int64) (*Dictionary, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return read(z)
+}
+
+func read(z *zip.Reader) (*Dictionary, error) {
+	var (
+		d         = &Dictionary{}
+		hasIndex  bool
+		termFiles []*zip.File
+		metaFiles []*zip.File
+	)
+	for _, f := range z.File {
+		name := path.Base(f.Name)
+		switch {
+		case name == "index.json":
+			if err := decodeFile(f, &d.Index); err != nil {
+				return nil, err
+			}
+			hasIndex = true
+		case strings.HasPrefix(name, "term_bank_") && strings.HasSuffix(name, ".json"):
+			termFiles = append(termFiles, f)
+		case strings.HasPrefix(name, "term_meta_bank_") && strings.HasSuffix(name, ".json"):
+			metaFiles = append(metaFiles, f)
+		}
+	}
+	if !hasIndex {
+		return nil, fmt.Errorf("index.json is missing")
+	}
+	format := d.Index.Format
+	if format == 0 {
+		format = d.Index.Version
+	}
+	if format < 1 || format > 3 {
+		return nil, fmt.Errorf("format %d isn't supported", format)
+	}
+
+	// banks are numbered from 1, and their order is the order of the terms
+	sortBanks(termFiles)
+	sortBanks(metaFiles)
+	for _, f := range termFiles {
+		var rows [][]json.RawMessage
+		if err := decodeFile(f, &rows); err != nil {
+			return nil, err
+		}
+		for i, row := range rows {
+			term, err := parseTerm(row, format)
+			if err != nil {
+				return nil, fmt.Errorf("%s row %d: %s", f.Name, i+1, err.Error())
+			}
+			d.Terms = append(d.Terms, term)
+		}
+	}
+	for _, f := range metaFiles {
+		var rows [][]json.RawMessage
+		if err := decodeFile(f, &rows); err != nil {
+			return nil, err
+		}
+		for i, row := range rows {
+			meta, err := parseTermMeta(row)
+			if err != nil {
+				return nil, fmt.Errorf("%s row %d: %s", f.Name, i+1, err.Error())
+			}
+			d.Meta = append(d.Meta, meta)
+		}
+	}
+	return d, nil
+}
+
+func decodeFile(f *zip.File, v interface{}) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %s", f.Name, err.Error())
+	}
+	return nil
+}
+
+// sortBanks sorts bank files by their number
+func sortBanks(files []*zip.File) {
+	number := func(f *zip.File) int {
+		var n int
+		name := strings.TrimSuffix(path.Base(f.Name), ".json")
+		fmt.Sscanf(name[strings.LastIndex(name, "_")+1:], "%d", &n)
+		return n
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return number(files[i]) < number(files[j])
+	})
+}
@@ -0,0 +1,108 @@
+package yomichan
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+func buildArchive(t *testing.T, files map[string]string) *bytes.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+var testArchive = map[string]string{
+	"index.json": `{"title": "Test Dictionary", "format": 3, "revision": "1", "sequenced": true}`,
+	"term_bank_1.json": `[
+		["食べる", "たべる", "v1", "v1", 10, ["to eat"], 1, "P"],
+		["喰べる", "たべる", "", "v1", 0, ["to eat (rare spelling)"], 1, ""],
+		["本", "ほん", null, "", 5, [{"type": "structured-content", "content": [{"tag": "div", "content": "book"}, {"tag": "div", "content": ["main ", {"tag": "span", "content": "part"}]}]}], 2, ""]
+	]`,
+	"term_meta_bank_1.json": `[
+		["食べる", "freq", {"reading": "たべる", "frequency": {"value": 500, "displayValue": "500㋕"}}],
+		["食べる", "pitch", {"reading": "たべる", "pitches": [{"position": 2}]}],
+		["本", "freq", 42],
+		["猫", "freq", 99]
+	]`,
+}
+
+func TestRead(t *testing.T) {
+	r := buildArchive(t, testArchive)
+	d, err := Read(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Index.Title != "Test Dictionary" {
+		t.Errorf("expected the title Test Dictionary, got %s", d.Index.Title)
+	}
+	if len(d.Terms) != 3 || len(d.Meta) != 4 {
+		t.Fatalf("expected 3 terms and 4 meta, got %d and %d", len(d.Terms), len(d.Meta))
+	}
+	if glossary := d.Terms[2].Glossary; len(glossary) != 1 || glossary[0] != "book main part" {
+		t.Errorf("expected structured content to be flattened, got %q", glossary)
+	}
+	if d.Meta[0].Frequency != "500㋕" || d.Meta[2].Frequency != "42" {
+		t.Errorf("unexpected frequencies %q and %q", d.Meta[0].Frequency, d.Meta[2].Frequency)
+	}
+	if len(d.Meta[1].Pitches) != 1 || d.Meta[1].Pitches[0] != 2 {
+		t.Errorf("expected a downstep at 2, got %v", d.Meta[1].Pitches)
+	}
+}
+
+func TestToJMdict(t *testing.T) {
+	r := buildArchive(t, testArchive)
+	d, err := Read(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := d.ToJMdict()
+	// the sequenced spellings of 食べる share an entry, and 猫 only has metadata
+	if len(j.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(j.Entries))
+	}
+	taberu := j.Entries[0]
+	if len(taberu.KanjiElements) != 2 || len(taberu.ReadingElements) != 1 {
+		t.Errorf("expected 2 spellings and 1 reading, got %+v", taberu)
+	}
+	// two terms, a frequency and a pitch accent
+	if len(taberu.Senses) != 4 {
+		t.Fatalf("expected 4 senses, got %+v", taberu.Senses)
+	}
+	if gloss := taberu.Senses[3].GlossaryItems[0].Definition; gloss != "Pitch accent: たべる [2]" {
+		t.Errorf("unexpected pitch gloss %s", gloss)
+	}
+	if gloss := j.Entries[1].Senses[1].GlossaryItems[0].Definition; gloss != "Frequency: 42" {
+		t.Errorf("expected the frequency of 本 to join its entry, got %s", gloss)
+	}
+
+	// metadata isn't found by searching glosses
+	dict := dictionary.New(j)
+	if matches := dict.SearchGloss("frequency", 0); len(matches) != 0 {
+		t.Errorf("expected no matches for metadata, got %v", matches)
+	}
+	if matches := dict.SearchGloss("book", 0); len(matches) != 1 {
+		t.Errorf("expected 本 to be found, got %v", matches)
+	}
+}
+
+func TestReadWithoutIndex(t *testing.T) {
+	r := buildArchive(t, map[string]string{"term_bank_1.json": "[]"})
+	if _, err := Read(r, r.Size()); err == nil {
+		t.Error("expected an error without index.json")
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/hakasec/japanbot-go/bot/dictionary"
+)
+
+// diffDictionaries prints the entries which changed between two
+// JMdict files, given as the arguments after diff
+func diffDictionaries(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: japanbot-go diff <old JMdict file> <new JMdict file>")
+	}
+
+	before, err := dictionary.Open(args[0], nil)
+	if err != nil {
+		return err
+	}
+	after, err := dictionary.Open(args[1], nil)
+	if err != nil {
+		return err
+	}
+	return dictionary.Compare(before, after).Write(os.Stdout, 0)
+}
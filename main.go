@@ -10,30 +10,48 @@ import (
 	"github.com/hakasec/japanbot-go/bot/config"
 )
 
-var botConfig *config.BotConfiguration
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		if err := diffDictionaries(os.Args[2:]); err != nil {
+			fmt.Printf("Error comparing dictionaries: %s\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
 
-func init() {
-	var err error
-	botConfig, err = config.LoadFromFile("./config.json")
+	botConfig, err := config.LoadFromFile("./config.json")
 	if err != nil {
-		panic(err)
+		fmt.Printf("Error loading config.json: %s\n", err.Error())
+		os.Exit(1)
 	}
-}
 
-func main() {
 	bot, err := bot.New(botConfig)
 	if err != nil {
-		panic(err)
+		fmt.Printf("Error loading the bot: %s\n", err.Error())
+		os.Exit(1)
 	}
 
 	err = bot.Start()
 	if err != nil {
-		panic(err)
+		fmt.Printf("Error starting the bot: %s\n", err.Error())
+		os.Exit(1)
 	}
 	defer bot.Stop()
 
 	fmt.Println("Bot is running!")
 
+	// SIGHUP reloads the dictionary, like jpn!reload dictionary
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			fmt.Println("Reloading the dictionary")
+			if err := bot.ReloadDictionary(); err != nil {
+				fmt.Printf("Error reloading the dictionary: %s\n", err.Error())
+			}
+		}
+	}()
+
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt, os.Kill)
 	<-sc